@@ -0,0 +1,348 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	sqlconverter "github.com/zhangleibg/sql-converter"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	usage = "Usage: sql.converter [<path>...] [-dsn=<dsn>] [-driver=<driver>] [-tags=<tags>] [-comment_tag=<comment_tag>] [-table_prefix=<table_prefix>] " +
+		"[-table_suffix=<table_suffix>] [-field_prefix=<field_prefix>] [-field_suffix=<field_suffix>] " +
+		"[-h] [-target=<target>] [-o=<output>] [-file_name=<file_name>] [-per_table] [-stdout] [-package=<package>] [-template=<template>] [-mode=<mode>] [-dry-run] [-diff] [-keep_case] [-fold_case] [-relations] " +
+		"[-null=<null>] [-null_types=<null_types>] [-dialect=<dialect>]\n" +
+		"       sql.converter inspect [<path>...] [-dsn=<dsn>] [-driver=<driver>] [-o=<output>] [-keep_case] [-fold_case] [-dialect=<dialect>]"
+	params = `
+Command:
+	inspect: 		write the schema IR of the tables as JSON instead of the go structs, a .json path reads the IR back
+
+Param:
+	path: 			the sql files, directories or glob patterns, directories are read recursively
+				and the directories of golang-migrate or goose migrations are read as migrations,
+				"-" reads the standard input, e.g. the output of mysql -e 'SHOW CREATE TABLE x',
+				a sqlite database file is read from its tables and a .json file is read as a schema IR
+	-dsn: 			the data source name of a running database whose tables are read from information_schema,
+				the path can be omitted, e.g. "user:password@tcp(127.0.0.1:3306)/shop"
+	-driver: 		the database/sql driver of the dsn, mysql, postgres or sqlite, default: the one of the dialect
+	-tags: 			field tag, default: "json,db",
+	-comment_tag: 	comment tag, default: "comment",
+	-table_prefix: 	the prefix of table name,
+	-table_suffix: 	the suffix of table name,
+	-field_prefix: 	the prefix of field name,
+	-field_prefix: 	the suffix of field name,
+	-h: 			the hint for usage,
+	-target: 		the directory of generated go file
+	-o: 			the generated go file, "-" for the standard output, default: <target>/generator.go,
+				or the schema IR file of inspect, default: the standard output
+	-file_name: 	the name of the generated file in <target>, default: generator.go, or the pattern of the file names
+				of -per_table, in which {table}, {name} and {struct} are replaced with the table name, the table name
+				without prefix and suffix and the struct name, default: {name}.go
+	-per_table: 	generate a file for each table in <target> instead of a single file
+	-stdout: 		write the generated code to the standard output, the same as -o=-
+	-package: 		the package of the generated code, default: the package of the go files in the output directory,
+				or the name of the directory when it has no go files
+	-template: 		a text/template file which renders the tables instead of the go structs,
+				the result is formatted by gofmt when it is go code
+	-mode: 			the write mode, APPEND, OVERWRITE or MERGE, default: MERGE for an existing go file and APPEND
+				otherwise, MERGE replaces the structs generated from the same tables in the existing file
+				and keeps the rest of it and the fields commented "// manual", go files can not be appended to
+	-dry-run: 		print the files which would be written and whether they are new, modified or unchanged,
+				instead of writing them, -dry_run is the same
+	-diff: 			print the unified diff of the files on disk and the generated ones instead of writing them
+	-keep_case: 	keep the case of table and field names written in the source, the default,
+				the unquoted names of POSTGRESQL are folded to lower case as the database does
+	-fold_case: 	fold the case of table and field names to lower case
+	-relations: 	add the association fields of the relations derived from the foreign keys to the structs,
+				joined by their gorm tags, e.g. User *Users with gorm:"foreignKey:UserID;references:ID"
+	-null: 			the type of nullable fields, NONE, SQL, POINTER or GUREGU, default: NONE, SQL and GUREGU
+				use pointers for the types without a wrapper of the same width, e.g. *int and *float32
+	-null_types: 	the null strategy of specific go types, e.g. "time.Time:POINTER,string:NONE"
+	-dialect: 		the sql dialect, MYSQL, POSTGRESQL, SQLITE or SQLSERVER, default: MYSQL
+`
+)
+
+type handlerFunc func(*sqlconverter.CreateTableSQLParser, string) error
+
+var flags = map[string]handlerFunc{
+	"-tags": func(cts *sqlconverter.CreateTableSQLParser, arg string) error {
+		tags := strings.Split(arg, ",")
+		if len(tags) == 0 {
+			return fmt.Errorf("empty tags parsed, %s", arg)
+		}
+		var params []string
+		for _, tag := range tags {
+			params = append(params, strings.TrimSpace(tag))
+		}
+		cts.Tags = params
+		return nil
+	},
+	"-comment_tag": func(cts *sqlconverter.CreateTableSQLParser, arg string) error {
+		if tag := strings.TrimSpace(arg); tag != "" {
+			cts.CommentTag = tag
+			return nil
+		}
+		return fmt.Errorf("empty comment_tag parsed, %s", arg)
+	},
+	"-table_prefix": func(cts *sqlconverter.CreateTableSQLParser, arg string) error {
+		if prefix := strings.TrimSpace(arg); prefix != "" {
+			cts.TableNamePrefix = prefix
+			return nil
+		}
+		return fmt.Errorf("empty table_prefix parsed, %s", arg)
+	},
+	"-table_suffix": func(cts *sqlconverter.CreateTableSQLParser, arg string) error {
+		if suffix := strings.TrimSpace(arg); suffix != "" {
+			cts.TableNameSuffix = suffix
+			return nil
+		}
+		return fmt.Errorf("empty table_suffix parsed, %s", arg)
+	},
+	"-field_prefix": func(cts *sqlconverter.CreateTableSQLParser, arg string) error {
+		if prefix := strings.TrimSpace(arg); prefix != "" {
+			cts.FieldNamePrefix = prefix
+			return nil
+		}
+		return fmt.Errorf("empty field_prefix parsed, %s", arg)
+	},
+	"-field_suffix": func(cts *sqlconverter.CreateTableSQLParser, arg string) error {
+		if suffix := strings.TrimSpace(arg); suffix != "" {
+			cts.FieldNameSuffix = suffix
+			return nil
+		}
+		return fmt.Errorf("empty field_suffix parsed, %s", arg)
+	},
+	"-h": func(cts *sqlconverter.CreateTableSQLParser, arg string) error {
+		fmt.Printf("%s%s", usage, params)
+		return nil
+	},
+	"-file": func(cts *sqlconverter.CreateTableSQLParser, s string) error {
+		if s == "" {
+			return fmt.Errorf("empty file parsed, %s", s)
+		}
+		if cts.SqlFile == "" {
+			cts.SqlFile = s
+			return nil
+		}
+		cts.SqlFiles = append(cts.SqlFiles, s)
+		return nil
+	},
+	"-dsn": func(cts *sqlconverter.CreateTableSQLParser, s string) error {
+		if dsn := strings.TrimSpace(s); dsn != "" {
+			cts.DSN = dsn
+			return nil
+		}
+		return fmt.Errorf("empty dsn parsed, %s", s)
+	},
+	"-driver": func(cts *sqlconverter.CreateTableSQLParser, s string) error {
+		if driver := strings.TrimSpace(s); driver != "" {
+			cts.Driver = driver
+			return nil
+		}
+		return fmt.Errorf("empty driver parsed, %s", s)
+	},
+	"-target": func(cts *sqlconverter.CreateTableSQLParser, s string) error {
+		cts.TargetDir = s
+		return nil
+	},
+	"-o": func(cts *sqlconverter.CreateTableSQLParser, s string) error {
+		if file := strings.TrimSpace(s); file != "" {
+			cts.OutputFile = file
+			return nil
+		}
+		return fmt.Errorf("empty output file parsed, %s", s)
+	},
+	"-file_name": func(cts *sqlconverter.CreateTableSQLParser, s string) error {
+		if name := strings.TrimSpace(s); name != "" {
+			cts.FileName = name
+			return nil
+		}
+		return fmt.Errorf("empty file_name parsed, %s", s)
+	},
+	"-per_table": func(cts *sqlconverter.CreateTableSQLParser, s string) error {
+		cts.PerTable = true
+		return nil
+	},
+	"-stdout": func(cts *sqlconverter.CreateTableSQLParser, s string) error {
+		cts.OutputFile = sqlconverter.StdoutPath
+		return nil
+	},
+	"-package": func(cts *sqlconverter.CreateTableSQLParser, s string) error {
+		name := strings.TrimSpace(s)
+		if !sqlconverter.IsPackageName(name) {
+			return fmt.Errorf("invalid package name parsed, %s", s)
+		}
+		cts.PackageName = name
+		return nil
+	},
+	"-template": func(cts *sqlconverter.CreateTableSQLParser, s string) error {
+		if file := strings.TrimSpace(s); file != "" {
+			cts.TemplateFile = file
+			return nil
+		}
+		return fmt.Errorf("empty template parsed, %s", s)
+	},
+	"-mode": func(cts *sqlconverter.CreateTableSQLParser, s string) error {
+		s = strings.ToUpper(strings.TrimSpace(s))
+		mode := sqlconverter.WriteMode(s)
+		if !mode.IsAllowed() {
+			return fmt.Errorf("write mode should be one of %v", sqlconverter.AllowedMode)
+		}
+		cts.Mode = mode
+		return nil
+	},
+	"-dry-run": func(cts *sqlconverter.CreateTableSQLParser, s string) error {
+		cts.DryRun = true
+		return nil
+	},
+	"-dry_run": func(cts *sqlconverter.CreateTableSQLParser, s string) error {
+		cts.DryRun = true
+		return nil
+	},
+	"-diff": func(cts *sqlconverter.CreateTableSQLParser, s string) error {
+		cts.Diff = true
+		return nil
+	},
+	"-keep_case": func(cts *sqlconverter.CreateTableSQLParser, s string) error {
+		// the case is kept unless -fold_case is set
+		return nil
+	},
+	"-fold_case": func(cts *sqlconverter.CreateTableSQLParser, s string) error {
+		cts.FoldIdentCase = true
+		return nil
+	},
+	"-relations": func(cts *sqlconverter.CreateTableSQLParser, s string) error {
+		cts.Relations = true
+		return nil
+	},
+	"-dialect": func(cts *sqlconverter.CreateTableSQLParser, s string) error {
+		dialect := sqlconverter.Dialect(strings.ToUpper(strings.TrimSpace(s)))
+		if !dialect.IsAllowed() {
+			return fmt.Errorf("dialect should be one of %v", sqlconverter.AllowedDialect)
+		}
+		cts.Dialect = dialect
+		return nil
+	},
+	"-null": func(cts *sqlconverter.CreateTableSQLParser, s string) error {
+		strategy := sqlconverter.NullStrategy(strings.ToUpper(strings.TrimSpace(s)))
+		if !strategy.IsAllowed() {
+			return fmt.Errorf("null strategy should be one of %v", sqlconverter.AllowedNullStrategy)
+		}
+		cts.NullStrategy = strategy
+		return nil
+	},
+	"-null_types": func(cts *sqlconverter.CreateTableSQLParser, s string) error {
+		overrides := make(map[sqlconverter.MappedGoFieldType]sqlconverter.NullStrategy)
+		for _, pair := range strings.Split(s, ",") {
+			eles := strings.Split(pair, ":")
+			if len(eles) != 2 {
+				return fmt.Errorf("null_types should be like <go type>:<null strategy>, %s", pair)
+			}
+			strategy := sqlconverter.NullStrategy(strings.ToUpper(strings.TrimSpace(eles[1])))
+			if !strategy.IsAllowed() {
+				return fmt.Errorf("null strategy should be one of %v", sqlconverter.AllowedNullStrategy)
+			}
+			overrides[sqlconverter.MappedGoFieldType(strings.TrimSpace(eles[0]))] = strategy
+		}
+		cts.NullOverrides = overrides
+		return nil
+	},
+}
+
+// parseArg returns the leading paths of the args as they are and the params of the flags after them
+func parseArg(args []string) ([]string, map[string]string, error) {
+	if len(args) == 0 {
+		return nil, nil, errors.New("the sql file is necessray")
+	}
+
+	flag2Param := make(map[string]string)
+	// the leading args should be the sql files, directories or glob patterns
+	var paths []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") && arg != sqlconverter.StdinPath {
+			break
+		}
+		if _, err := os.Stat(arg); os.IsNotExist(err) && !sqlconverter.IsGlob(arg) && arg != sqlconverter.StdinPath {
+			return nil, nil, fmt.Errorf("%s doesn't exist", arg)
+		}
+		paths = append(paths, arg)
+	}
+
+	for i := len(paths); i < len(args); i++ {
+		if !strings.HasPrefix(args[i], "-") || args[i] == sqlconverter.StdinPath {
+			return nil, nil, fmt.Errorf("%s is not a correct flag", args[i])
+		}
+		eles := strings.Split(args[i], "=")
+
+		var (
+			flag  string
+			param string
+		)
+		flag = eles[0]
+		if len(eles) > 1 {
+			param = eles[1]
+		}
+		// a dsn may contain "=", e.g. host=localhost dbname=shop
+		if flag == "-dsn" {
+			param = strings.Join(eles[1:], "=")
+		}
+		if _, exist := flags[flag]; !exist {
+			return nil, nil, fmt.Errorf("%s is not within the given flags", flag)
+		}
+		flag2Param[flag] = param
+	}
+	if _, exist := flag2Param["-dsn"]; !exist && len(paths) == 0 {
+		return nil, nil, fmt.Errorf("the 1st arg passed should be the sql file, passed: %s", args[0])
+	}
+	return paths, flag2Param, nil
+}
+
+func getParser(paths []string, flag2param map[string]string) (*sqlconverter.CreateTableSQLParser, error) {
+	cts := &sqlconverter.CreateTableSQLParser{}
+	for _, path := range paths {
+		if err := flags["-file"](cts, path); err != nil {
+			return nil, fmt.Errorf("param parsing failed, path: %s, error: %v", path, err)
+		}
+	}
+	for flag, param := range flag2param {
+		err := flags[flag](cts, param)
+		if err != nil {
+			return nil, fmt.Errorf("param parsing failed, flag: %s, param: %s, error: %v", flag, param, err)
+		}
+	}
+	return cts, nil
+}
+
+// inspectCommand writes the schema IR instead of the go structs
+const inspectCommand = "inspect"
+
+func main() {
+	args := os.Args[1:]
+	inspect := len(args) > 0 && args[0] == inspectCommand
+	if inspect {
+		args = args[1:]
+	}
+	paths, flag2param, err := parseArg(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	parser, err := getParser(paths, flag2param)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	run := parser.Parse
+	if inspect {
+		run = parser.Inspect
+	}
+	if err = run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseArg(t *testing.T) {
+	// a path may contain the separator of path lists
+	dir := filepath.Join(t.TempDir(), "a:b")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "x.sql")
+	if err := ioutil.WriteFile(file, []byte("CREATE TABLE t (id int)"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string][]string{
+		"Case1": {
+			"-aa", "-file",
+		},
+		"Case2": {
+			"../../test.sql", "-aa", "-file",
+		},
+		"Case3": {
+			"../../test.sql",
+			"-tags=json,db",
+			"-comment_tag",
+		},
+		"Case4": {
+			"../../test.sql",
+			"-tags=json,db",
+			"-comment_tag=comment",
+			"-field_prefix=v_",
+			"-table_prefix=v_",
+			"-table_suffix=v_=v_",
+		},
+		"Case5": {
+			"../../test.sql", "../../testdata/tables", "../../testdata/tables/shop/*.sql", "-mode=OVERWRITE",
+		},
+		"Case6": {
+			"../../test.sql", "../../unknown.sql",
+		},
+		"Case7": {
+			"-dsn=host=localhost dbname=shop", "-driver=postgres", "-dialect=POSTGRESQL",
+		},
+		"Case8": {
+			file, "../../test.sql", "-stdout",
+		},
+	}
+	expectedPaths := map[string][]string{
+		"Case3": {"../../test.sql"},
+		"Case4": {"../../test.sql"},
+		"Case5": {"../../test.sql", "../../testdata/tables", "../../testdata/tables/shop/*.sql"},
+		"Case8": {file, "../../test.sql"},
+	}
+	expected := map[string]map[string]string{
+		"Case1": nil,
+		"Case2": nil,
+		"Case3": {
+			"-tags":        "json,db",
+			"-comment_tag": "",
+		},
+		"Case4": {
+			"-tags":         "json,db",
+			"-comment_tag":  "comment",
+			"-field_prefix": "v_",
+			"-table_prefix": "v_",
+			"-table_suffix": "v_",
+		},
+		"Case5": {
+			"-mode": "OVERWRITE",
+		},
+		"Case6": nil,
+		"Case7": {
+			"-dsn":     "host=localhost dbname=shop",
+			"-driver":  "postgres",
+			"-dialect": "POSTGRESQL",
+		},
+		"Case8": {
+			"-stdout": "",
+		},
+	}
+
+	for name, args := range cases {
+		t.Run(name, func(t *testing.T) {
+			paths, parser, _ := parseArg(args)
+			assert.Equal(t, expectedPaths[name], paths)
+			assert.Equal(t, expected[name], parser)
+		})
+	}
+
+	paths, flag2param, err := parseArg(cases["Case8"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser, err := getParser(paths, flag2param)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, file, parser.SqlFile)
+	assert.Equal(t, []string{"../../test.sql"}, parser.SqlFiles)
+}
+
+func TestIdentCase(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "users.sql")
+	if err := ioutil.WriteFile(file, []byte("CREATE TABLE UserInfo (UserID int NOT NULL, `NickName` varchar(8) NOT NULL);"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string][]string{
+		"Keep": {file, "-package=models"},
+		"Fold": {file, "-package=models", "-fold_case"},
+	}
+	expected := map[string]string{
+		"Keep": "package models\n\n// table: UserInfo\ntype UserInfo struct {\n" +
+			"\tUserID   int32  `json:\"UserID\" db:\"UserID\"`\n" +
+			"\tNickName string `json:\"NickName\" db:\"NickName\"`\n}\n",
+		"Fold": "package models\n\n// table: userinfo\ntype Userinfo struct {\n" +
+			"\tUserid   int32  `json:\"userid\" db:\"userid\"`\n" +
+			"\tNickname string `json:\"nickname\" db:\"nickname\"`\n}\n",
+	}
+	for name, args := range cases {
+		t.Run(name, func(t *testing.T) {
+			output := filepath.Join(dir, name+".go")
+			paths, flag2param, err := parseArg(append(args, "-o="+output))
+			if err != nil {
+				t.Fatal(err)
+			}
+			parser, err := getParser(paths, flag2param)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := parser.Parse(); err != nil {
+				t.Fatal(err)
+			}
+			b, err := ioutil.ReadFile(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, expected[name], string(b))
+		})
+	}
+}
@@ -1,4 +1,4 @@
-package main
+package sqlconverter
 
 import (
 	"strings"
@@ -15,12 +15,100 @@ const (
 	FeildTypeFloat64 MappedGoFieldType = "float64"
 	FeildTypeFloat32 MappedGoFieldType = "float32"
 	FieldTypeTime    MappedGoFieldType = "time.Time"
+	FieldTypeBool    MappedGoFieldType = "bool"
+	FieldTypeBytes   MappedGoFieldType = "[]byte"
+	FieldTypeJSON    MappedGoFieldType = "json.RawMessage"
 )
 
 func (t MappedGoFieldType) getString() string {
 	return string(t)
 }
 
+// isNilable reports whether nil of the type already stands for NULL
+func (t MappedGoFieldType) isNilable() bool {
+	return t == FeildTypeDefault || t == FieldTypeJSON || strings.HasPrefix(string(t), "[]")
+}
+
+type NullStrategy string
+
+const (
+	NullNone    NullStrategy = "NONE"    // nullable fields keep the plain type
+	NullSQL     NullStrategy = "SQL"     // sql.NullString, sql.NullInt64, sql.NullTime...
+	NullPointer NullStrategy = "POINTER" // *string, *int64, *time.Time...
+	NullGuregu  NullStrategy = "GUREGU"  // null.String, null.Int, null.Time... of gopkg.in/guregu/null.v4
+)
+
+// nullTypes are the wrappers of the same width as the go types, the types without one are wrapped by pointers
+
+var AllowedNullStrategy = []NullStrategy{NullNone, NullSQL, NullPointer, NullGuregu}
+
+func (s NullStrategy) IsAllowed() bool {
+	for _, strategy := range AllowedNullStrategy {
+		if strategy == s {
+			return true
+		}
+	}
+	return false
+}
+
+var nullTypes = map[NullStrategy]map[MappedGoFieldType]MappedGoFieldType{
+	NullSQL: {
+		FeildTypeString:  "sql.NullString",
+		FeildTypeInt64:   "sql.NullInt64",
+		FeildTypeInt32:   "sql.NullInt32",
+		FeildTypeFloat64: "sql.NullFloat64",
+		FieldTypeTime:    "sql.NullTime",
+		FieldTypeBool:    "sql.NullBool",
+	},
+	NullGuregu: {
+		FeildTypeString:  "null.String",
+		FeildTypeInt64:   "null.Int",
+		FeildTypeFloat64: "null.Float",
+		FieldTypeTime:    "null.Time",
+		FieldTypeBool:    "null.Bool",
+	},
+}
+
+// wrap returns the go type holding a nullable column of type t
+func (s NullStrategy) wrap(t MappedGoFieldType) MappedGoFieldType {
+	if t.isNilable() {
+		return t
+	}
+	switch s {
+	case NullPointer:
+		return "*" + t
+	case NullSQL, NullGuregu:
+		if nullType, exist := nullTypes[s][t]; exist {
+			return nullType
+		}
+		return "*" + t
+	}
+	return t
+}
+
+// typeImports maps the package qualifier of a go type to its import path
+var typeImports = map[string]string{
+	"sql":  "database/sql",
+	"time": "time",
+	"json": "encoding/json",
+	"null": "gopkg.in/guregu/null.v4",
+}
+
+// importPath returns the import path needed by the go type, empty for builtin types
+func (t MappedGoFieldType) importPath() string {
+	name := strings.TrimLeft(string(t), "*[]")
+	idx := strings.Index(name, ".")
+	if idx < 0 {
+		return ""
+	}
+	return typeImports[name[:idx]]
+}
+
+// TypeMapper maps the type name of a sql field to a go type
+type TypeMapper interface {
+	getGoStructType(sqlField string) MappedGoFieldType
+}
+
 type FieldTypeMapper map[MappedGoFieldType][]string
 
 func (m FieldTypeMapper) getGoStructType(sqlField string) MappedGoFieldType {
@@ -53,7 +141,10 @@ func init() {
 		"INT", "INTEGER",
 	}
 	mapping[FeildTypeInt] = []string{
-		"BIT", "BOOL", "BOOLEAN", "SMALLINT", "MEDIUMINT",
+		"BIT", "SMALLINT", "MEDIUMINT",
+	}
+	mapping[FieldTypeBool] = []string{
+		"BOOL", "BOOLEAN",
 	}
 	mapping[FeildTypeFloat64] = []string{
 		"DOUBLE", "DECIMAL", "DEC",
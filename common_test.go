@@ -1,4 +1,4 @@
-package main
+package sqlconverter
 
 import (
 	"fmt"
@@ -11,11 +11,13 @@ func TestGetGoStructType(t *testing.T) {
 	inputs := []string{
 		"bigint",
 		"varchar",
+		"boolean",
 		"aa",
 	}
 	expecteds := []string{
 		"int64",
 		"string",
+		"bool",
 		"interface{}",
 	}
 
@@ -28,3 +30,53 @@ func TestGetGoStructType(t *testing.T) {
 	}
 
 }
+
+func TestNullStrategyWrap(t *testing.T) {
+	inputs := []MappedGoFieldType{
+		FeildTypeString,
+		FeildTypeInt,
+		FeildTypeInt32,
+		FeildTypeInt64,
+		FeildTypeFloat32,
+		FieldTypeBool,
+		FieldTypeTime,
+		FeildTypeDefault,
+	}
+	expecteds := map[NullStrategy][]MappedGoFieldType{
+		NullNone:    {"string", "int", "int32", "int64", "float32", "bool", "time.Time", "interface{}"},
+		NullSQL:     {"sql.NullString", "*int", "sql.NullInt32", "sql.NullInt64", "*float32", "sql.NullBool", "sql.NullTime", "interface{}"},
+		NullPointer: {"*string", "*int", "*int32", "*int64", "*float32", "*bool", "*time.Time", "interface{}"},
+		NullGuregu:  {"null.String", "*int", "*int32", "null.Int", "*float32", "null.Bool", "null.Time", "interface{}"},
+	}
+
+	for strategy, expected := range expecteds {
+		t.Run(string(strategy), func(t *testing.T) {
+			var actual []MappedGoFieldType
+			for _, input := range inputs {
+				actual = append(actual, strategy.wrap(input))
+			}
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestImportPath(t *testing.T) {
+	inputs := []MappedGoFieldType{
+		"string",
+		"*time.Time",
+		"sql.NullString",
+		"null.Int",
+	}
+	expecteds := []string{
+		"",
+		"time",
+		"database/sql",
+		"gopkg.in/guregu/null.v4",
+	}
+
+	for i, input := range inputs {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			assert.Equal(t, expecteds[i], input.importPath())
+		})
+	}
+}
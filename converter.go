@@ -0,0 +1,106 @@
+// Package sqlconverter generates go structs from the CREATE TABLE statements of MySQL, PostgreSQL,
+// SQLite and SQL Server, or from the tables of a running database.
+//
+// Parse reads the statements into a Schema and Generate turns the schema into go files:
+//
+//	schema, err := sqlconverter.Parse(file, sqlconverter.Options{Dialect: sqlconverter.MySQL})
+//	files, err := sqlconverter.Generate(schema, sqlconverter.GeneratorOptions{Tags: []string{"json", "db"}})
+//
+// The command sql-converter in cmd/sql-converter is a wrapper of CreateTableSQLParser, which also reads
+// sql files, directories, migrations and databases, and writes the generated file
+package sqlconverter
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// DefaultFileName is the name of the generated file
+const DefaultFileName = "generator.go"
+
+// DefaultTableFileName is the pattern of the file names of the tables when a file is generated for each table,
+// e.g. student_info.go for the table v_student_info with the table prefix v_
+const DefaultTableFileName = "{name}.go"
+
+// Parse reads the statements from r and applies the ones which define or change tables to a new schema in order,
+// the statements which do not define or change tables are skipped
+func Parse(r io.Reader, opts Options) (*Schema, error) {
+	if opts.Dialect == "" {
+		opts.Dialect = MySQL
+	}
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read sql failed, err: %v", err)
+	}
+	sqls, err := readCreateSQL(content, opts.Dialect)
+	if err != nil {
+		return nil, err
+	}
+	schema := &Schema{}
+	for _, sql := range sqls {
+		stmt, err := parseStatement(sql, opts)
+		if err != nil {
+			return nil, err
+		}
+		schema.Apply(stmt)
+	}
+	return schema, nil
+}
+
+// GeneratorOptions controls how the go structs are generated from the tables
+type GeneratorOptions struct {
+	Package         string                             // the package of the generated code, default: the package or the name of the working directory
+	Template        string                             // the text/template which renders the schema instead of the structs, see TemplateData
+	PerTable        bool                               // generate a file for each table instead of a single file
+	FileName        string                             // the name of the file, or the pattern of the file names of the tables, see DefaultTableFileName
+	Tags            []string                           // the tags of the fields, default: json, db
+	CommentTag      string                             // the tag holding the comment of the field, default: alias
+	Converter       ConvertFunc                        // converts the table and field names into go names, default: upper camel case
+	TableNamePrefix string                             // trimmed from the table names
+	TableNameSuffix string                             // trimmed from the table names
+	FieldNamePrefix string                             // trimmed from the field names
+	FieldNameSuffix string                             // trimmed from the field names
+	Dialect         Dialect                            // maps the sql types to go types, default: MYSQL
+	NullStrategy    NullStrategy                       // how nullable fields are typed, default: NONE
+	NullOverrides   map[MappedGoFieldType]NullStrategy // the null strategy of specific go types
+	Relations       bool                               // add the association fields of the relations with their gorm tags
+}
+
+// File is a generated go file
+type File struct {
+	Name    string
+	Content []byte
+}
+
+// Generate generates the go structs of the tables of the schema into a file, or a file for each table,
+// the relations between the tables are generated as association fields when Relations is set
+func Generate(schema *Schema, opts GeneratorOptions) ([]File, error) {
+	if schema == nil {
+		return nil, fmt.Errorf("nil schema")
+	}
+	parser := &CreateTableSQLParser{
+		Tags:            opts.Tags,
+		CommentTag:      opts.CommentTag,
+		Converter:       opts.Converter,
+		TableNamePrefix: opts.TableNamePrefix,
+		TableNameSuffix: opts.TableNameSuffix,
+		FieldNamePrefix: opts.FieldNamePrefix,
+		FieldNameSuffix: opts.FieldNameSuffix,
+		Dialect:         opts.Dialect,
+		NullStrategy:    opts.NullStrategy,
+		NullOverrides:   opts.NullOverrides,
+		PackageName:     opts.Package,
+		templateText:    opts.Template,
+		PerTable:        opts.PerTable,
+		FileName:        opts.FileName,
+		Relations:       opts.Relations,
+		schema:          *schema,
+	}
+	parser.SetDefault()
+	if !IsPackageName(parser.PackageName) {
+		return nil, fmt.Errorf("invalid package name %q", parser.PackageName)
+	}
+	parser.generate()
+	return parser.files()
+}
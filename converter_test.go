@@ -0,0 +1,134 @@
+package sqlconverter
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	sql := "CREATE TABLE users (id BIGINT NOT NULL AUTO_INCREMENT, name VARCHAR(64), PRIMARY KEY (id));\n" +
+		"INSERT INTO users VALUES (1, 'a');\n" +
+		"ALTER TABLE users ADD COLUMN email VARCHAR(128) NOT NULL AFTER id, DROP COLUMN name;\n" +
+		"CREATE TABLE logs (id INT);\n" +
+		"DROP TABLE logs;"
+	schema, err := Parse(strings.NewReader(sql), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, schema.Tables, 1)
+	users := schema.GetTable("users")
+	var names []string
+	for _, field := range users.Fields {
+		names = append(names, field.FieldName)
+	}
+	assert.Equal(t, []string{"id", "email"}, names)
+	assert.Equal(t, []string{"id"}, users.PrimaryKey.Columns)
+
+	_, err = Parse(strings.NewReader("CREATE TABLE t (id INT"), Options{})
+	assert.Error(t, err)
+}
+
+func TestGenerate(t *testing.T) {
+	f, err := os.Open("./testdata/mysqldump.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	schema, err := Parse(f, Options{Dialect: MySQL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := Generate(schema, GeneratorOptions{CommentTag: "comment", NullStrategy: NullPointer, Package: "models"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the same as the code generated by the parser of the command
+	parser := &CreateTableSQLParser{
+		SqlFile:      "./testdata/mysqldump.sql",
+		CommentTag:   "comment",
+		NullStrategy: NullPointer,
+		PackageName:  "models",
+	}
+	parser.SetDefault()
+	if err := parser.load(); err != nil {
+		t.Fatal(err)
+	}
+	if err := parser.parseSQL(); err != nil {
+		t.Fatal(err)
+	}
+	code, err := parser.format()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []File{{Name: DefaultFileName, Content: code}}, files)
+	assert.Contains(t, string(files[0].Content), "type Users struct {")
+	assert.True(t, strings.HasPrefix(string(files[0].Content), "package models\n"))
+
+	// the package of the go files in the working directory
+	files, err = Generate(schema, GeneratorOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, strings.HasPrefix(string(files[0].Content), "package sqlconverter\n"))
+
+	_, err = Generate(nil, GeneratorOptions{})
+	assert.Error(t, err)
+	_, err = Generate(schema, GeneratorOptions{Package: "my-models"})
+	assert.Error(t, err)
+}
+
+func TestGeneratePerTable(t *testing.T) {
+	sql := "CREATE TABLE v_student_info (id bigint NOT NULL, created_at datetime NOT NULL);\n" +
+		"CREATE TABLE v_class (id int NOT NULL, name varchar(32))"
+	schema, err := Parse(strings.NewReader(sql), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := Generate(schema, GeneratorOptions{Package: "models", TableNamePrefix: "v_", PerTable: true, NullStrategy: NullSQL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// each file only imports the packages of its own table
+	assert.Equal(t, []File{
+		{Name: "student_info.go", Content: []byte("package models\n\nimport \"time\"\n\n// table: v_student_info\ntype StudentInfo struct {\n" +
+			"\tID        int64     `json:\"id\" db:\"id\"`\n" +
+			"\tCreatedAt time.Time `json:\"created_at\" db:\"created_at\"`\n}\n")},
+		{Name: "class.go", Content: []byte("package models\n\nimport \"database/sql\"\n\n// table: v_class\ntype Class struct {\n" +
+			"\tID   int32          `json:\"id\" db:\"id\"`\n" +
+			"\tName sql.NullString `json:\"name\" db:\"name\"`\n}\n")},
+	}, files)
+
+	patterns := []string{"{table}.go", "{struct}_gen.go", "models.go", "{name}/model.go"}
+	expecteds := [][]string{
+		{"v_student_info.go", "v_class.go"},
+		{"StudentInfo_gen.go", "Class_gen.go"},
+		nil,
+		nil,
+	}
+	for i, pattern := range patterns {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			files, err := Generate(schema, GeneratorOptions{Package: "models", TableNamePrefix: "v_", PerTable: true, FileName: pattern})
+			var names []string
+			for _, file := range files {
+				names = append(names, file.Name)
+			}
+			assert.Equal(t, expecteds[i], names)
+			assert.Equal(t, expecteds[i] == nil, err != nil)
+		})
+	}
+
+	files, err = Generate(schema, GeneratorOptions{Package: "models", FileName: "models.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, files, 1)
+	assert.Equal(t, "models.go", files[0].Name)
+}
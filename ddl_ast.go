@@ -0,0 +1,154 @@
+package sqlconverter
+
+// Statement is one of the parsed statements: *CreateTableStmt, *CreateViewStmt, *CreateIndexStmt,
+// *AlterTableStmt, *RenameTableStmt, *DropTableStmt, *DropIndexStmt, *CommentStmt
+type Statement interface {
+	statement()
+}
+
+// CreateTableStmt is the syntax tree of a CREATE TABLE statement
+type CreateTableStmt struct {
+	Schema      string
+	Table       string
+	IfNotExists bool
+	Columns     []*ColumnDef
+	Constraints []*ConstraintDef
+	ForeignKeys []*ForeignKeyDef
+	Options     []*TableOption
+}
+
+type ColumnDef struct {
+	Name          string
+	Type          *DataType // nil when the column is declared without a type
+	Comment       string
+	NotNull       bool
+	Default       *string // the source text of the default expression, nil when there is no DEFAULT clause
+	AutoIncrement bool
+	Unsigned      bool
+	Zerofill      bool
+	Charset       string
+	Collation     string
+	OnUpdate      string // the source text of the ON UPDATE expression
+	PrimaryKey    bool
+	Unique        bool
+	References    *ForeignKeyDef // the inline REFERENCES clause, its Columns is empty
+}
+
+type DataType struct {
+	Name  string   // the words of multi-word types are joined by a single space, e.g. double precision
+	Args  []string // the arguments in parentheses, e.g. length, precision or enum values
+	Array bool     // the type is an array of Name, e.g. text[]
+}
+
+type TableOption struct {
+	Name  string
+	Value string
+}
+
+// ConstraintDef is a table level key or index definition, e.g. UNIQUE KEY `uk_name` (`name`)
+type ConstraintDef struct {
+	Kind    IndexKind
+	Name    string
+	Columns []string
+}
+
+type ForeignKeyDef struct {
+	Name       string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	OnDelete   string
+	OnUpdate   string
+}
+
+// CommentStmt is the syntax tree of COMMENT ON TABLE and COMMENT ON COLUMN statements
+type CommentStmt struct {
+	Table   string
+	Column  string // empty when the comment is on the table
+	Comment string
+}
+
+// CreateViewStmt is a CREATE VIEW statement, only the name of the view is kept
+type CreateViewStmt struct {
+	View string
+}
+
+// CreateIndexStmt is the syntax tree of a CREATE INDEX statement
+type CreateIndexStmt struct {
+	Table string
+	Index *ConstraintDef
+}
+
+// AlterTableStmt is the syntax tree of an ALTER TABLE statement, the specifications which do not
+// change the structure of the table, e.g. ENGINE=InnoDB or DISABLE KEYS, are not kept
+type AlterTableStmt struct {
+	Table string
+	Specs []*AlterSpec
+}
+
+type AlterAction string
+
+const (
+	AlterAddColumn      AlterAction = "ADD COLUMN"
+	AlterDropColumn     AlterAction = "DROP COLUMN"
+	AlterModifyColumn   AlterAction = "MODIFY COLUMN" // replaces the definition of the column Name by Column
+	AlterRenameColumn   AlterAction = "RENAME COLUMN"
+	AlterSetType        AlterAction = "SET TYPE"
+	AlterSetNotNull     AlterAction = "SET NOT NULL"
+	AlterDropNotNull    AlterAction = "DROP NOT NULL"
+	AlterSetDefault     AlterAction = "SET DEFAULT"
+	AlterDropDefault    AlterAction = "DROP DEFAULT"
+	AlterAddIndex       AlterAction = "ADD INDEX"
+	AlterAddForeignKey  AlterAction = "ADD FOREIGN KEY"
+	AlterDropIndex      AlterAction = "DROP INDEX"
+	AlterDropPrimaryKey AlterAction = "DROP PRIMARY KEY"
+	AlterDropForeignKey AlterAction = "DROP FOREIGN KEY"
+	AlterDropConstraint AlterAction = "DROP CONSTRAINT" // drops the index or foreign key named Name
+	AlterRenameIndex    AlterAction = "RENAME INDEX"
+	AlterRenameTable    AlterAction = "RENAME TABLE"
+)
+
+// AlterSpec is one specification of ALTER TABLE, CHANGE COLUMN is a MODIFY COLUMN whose Column has a new name
+type AlterSpec struct {
+	Action     AlterAction
+	Name       string         // the column, index or constraint which is altered
+	NewName    string         // the new name of RENAME actions
+	Column     *ColumnDef     // the new definition of ADD COLUMN and MODIFY COLUMN
+	First      bool           // the column is placed first by FIRST
+	After      string         // the column is placed after this one by AFTER
+	Type       *DataType      // the new type of SET TYPE
+	Default    *string        // the source text of the default expression of SET DEFAULT
+	Constraint *ConstraintDef // the key of ADD INDEX
+	ForeignKey *ForeignKeyDef // the foreign key of ADD FOREIGN KEY
+}
+
+// TableRename is one old TO new pair of RENAME TABLE
+type TableRename struct {
+	Old string
+	New string
+}
+
+// RenameTableStmt is the syntax tree of a RENAME TABLE statement
+type RenameTableStmt struct {
+	Renames []*TableRename
+}
+
+// DropTableStmt is the syntax tree of a DROP TABLE statement
+type DropTableStmt struct {
+	Tables []string
+}
+
+// DropIndexStmt is the syntax tree of a DROP INDEX statement
+type DropIndexStmt struct {
+	Table string // empty when the index is dropped by its name only, e.g. in PostgreSQL
+	Name  string
+}
+
+func (*CreateTableStmt) statement() {}
+func (*CreateViewStmt) statement()  {}
+func (*CreateIndexStmt) statement() {}
+func (*AlterTableStmt) statement()  {}
+func (*RenameTableStmt) statement() {}
+func (*DropTableStmt) statement()   {}
+func (*DropIndexStmt) statement()   {}
+func (*CommentStmt) statement()     {}
@@ -0,0 +1,1225 @@
+package sqlconverter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// tableConstraintKeywords start a table level constraint instead of a column definition
+var tableConstraintKeywords = []string{
+	"PRIMARY", "KEY", "INDEX", "UNIQUE", "CONSTRAINT", "FOREIGN", "CHECK", "FULLTEXT", "SPATIAL", "LIKE",
+}
+
+// columnConstraintKeywords start a column constraint, so they can not be the type of a column
+var columnConstraintKeywords = []string{
+	"NOT", "NULL", "PRIMARY", "UNIQUE", "DEFAULT", "CHECK", "REFERENCES", "CONSTRAINT", "COLLATE", "GENERATED", "AS",
+}
+
+// ignoredColumnOptions are the column options which do not change the generated field, e.g. the storage or
+// the visibility of the column, and the modifiers of its constraints
+var ignoredColumnOptions = []string{
+	"STORED", "VIRTUAL", "PERSISTED", "VISIBLE", "INVISIBLE", "SIGNED", "BINARY", "ASCII", "UNICODE",
+	"ROWGUIDCOL", "SPARSE", "FILESTREAM", "CLUSTERED", "NONCLUSTERED", "ASC", "DESC", "DEFERRABLE", "ENFORCED",
+}
+
+// createdObjects are the kinds of objects which can be created by CREATE statements
+var createdObjects = []string{
+	"TABLE", "VIEW", "INDEX", "TRIGGER", "PROCEDURE", "FUNCTION", "EVENT", "DATABASE", "SCHEMA", "SEQUENCE",
+	"TYPE", "USER", "ROLE", "EXTENSION", "TABLESPACE", "SERVER",
+}
+
+// createTableModifiers may appear between CREATE and TABLE
+var createTableModifiers = []string{
+	"OR", "REPLACE", "TEMPORARY", "TEMP", "GLOBAL", "LOCAL", "UNLOGGED",
+}
+
+// Options controls how the DDL is read, keywords are always compared case-insensitively
+// and comments or string literals always keep their original case
+type Options struct {
+	Dialect       Dialect // the sql dialect, default: MYSQL
+	FoldIdentCase bool    // fold table and column names to lower case instead of keeping the case of the source
+}
+
+type ddlParser struct {
+	src    []rune
+	tokens []Token
+	pos    int
+	opts   Options
+}
+
+func newDDLParser(sql string, opts Options) (*ddlParser, error) {
+	tokens, err := tokenize(sql, opts.Dialect.lexerConfig())
+	if err != nil {
+		return nil, err
+	}
+	return &ddlParser{src: []rune(sql), tokens: tokens, opts: opts}, nil
+}
+
+// parseStatement parses one statement, nil is returned for statements which do not define or change tables
+func parseStatement(sql string, opts Options) (Statement, error) {
+	p, err := newDDLParser(sql, opts)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case p.isKeyword("CREATE") && p.createdObject() == "VIEW":
+		return p.parseCreateView()
+	case p.isKeyword("CREATE") && p.createdObject() == "INDEX":
+		return p.parseCreateIndex()
+	case p.isKeyword("CREATE"):
+		stmt, err := p.parseCreateTable()
+		if stmt == nil || err != nil {
+			return nil, err
+		}
+		return stmt, nil
+	case p.isKeyword("ALTER"):
+		stmt, err := p.parseAlterTable()
+		if stmt == nil || err != nil {
+			return nil, err
+		}
+		return stmt, nil
+	case p.isKeyword("RENAME"):
+		stmt, err := p.parseRenameTable()
+		if stmt == nil || err != nil {
+			return nil, err
+		}
+		return stmt, nil
+	case p.isKeyword("DROP", "INDEX"):
+		return p.parseDropIndex()
+	case p.isKeyword("DROP"):
+		stmt, err := p.parseDropTable()
+		if stmt == nil || err != nil {
+			return nil, err
+		}
+		return stmt, nil
+	case p.isKeyword("COMMENT", "ON"):
+		stmt, err := p.parseComment()
+		if stmt == nil || err != nil {
+			return nil, err
+		}
+		return stmt, nil
+	}
+	return nil, nil
+}
+
+// parseCreateTable parses one CREATE TABLE statement, nil is returned when the
+// statement creates something else than a table
+func parseCreateTable(sql string, opts Options) (*CreateTableStmt, error) {
+	p, err := newDDLParser(sql, opts)
+	if err != nil {
+		return nil, err
+	}
+	return p.parseCreateTable()
+}
+
+// parseTypeName parses a declared type such as VARCHAR(64), e.g. the type of a column reported
+// by the database, nil is returned for an empty type
+func parseTypeName(name string, opts Options) (*DataType, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, nil
+	}
+	p, err := newDDLParser(name, opts)
+	if err != nil {
+		return nil, err
+	}
+	return p.parseDataType()
+}
+
+func (p *ddlParser) peek() Token {
+	return p.peekAt(0)
+}
+
+func (p *ddlParser) peekAt(offset int) Token {
+	if p.pos+offset >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+offset]
+}
+
+func (p *ddlParser) next() Token {
+	tok := p.peek()
+	if p.pos < len(p.tokens)-1 {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *ddlParser) eof() bool {
+	tok := p.peek()
+	return tok.Kind == TokenEOF || tok.IsPunct(";")
+}
+
+// isKeyword reports whether the upcoming tokens are the given words
+func (p *ddlParser) isKeyword(words ...string) bool {
+	for i, word := range words {
+		if !p.peekAt(i).IsKeyword(word) {
+			return false
+		}
+	}
+	return true
+}
+
+// isAnyKeyword reports whether the upcoming token is one of the given words
+func (p *ddlParser) isAnyKeyword(words []string) bool {
+	return p.peek().IsAnyKeyword(words)
+}
+
+func (p *ddlParser) acceptKeyword(words ...string) bool {
+	if !p.isKeyword(words...) {
+		return false
+	}
+	p.pos += len(words)
+	return true
+}
+
+func (p *ddlParser) expectKeyword(words ...string) error {
+	if !p.acceptKeyword(words...) {
+		return p.errorf("expected %s", strings.Join(words, " "))
+	}
+	return nil
+}
+
+func (p *ddlParser) acceptPunct(punct string) bool {
+	if !p.peek().IsPunct(punct) {
+		return false
+	}
+	p.next()
+	return true
+}
+
+func (p *ddlParser) expectPunct(punct string) error {
+	if !p.acceptPunct(punct) {
+		return p.errorf("expected %q", punct)
+	}
+	return nil
+}
+
+func (p *ddlParser) errorf(format string, args ...interface{}) error {
+	tok := p.peek()
+	return fmt.Errorf("line %d: %s, got %s", tok.Line, fmt.Sprintf(format, args...), tok)
+}
+
+func (p *ddlParser) parseIdent() (string, error) {
+	tok := p.peek()
+	if tok.Kind != TokenIdent && tok.Kind != TokenQuotedIdent {
+		return "", p.errorf("expected identifier")
+	}
+	p.next()
+	// the unquoted identifiers of PostgreSQL are folded as the database does
+	if p.opts.FoldIdentCase || tok.Kind == TokenIdent && p.opts.Dialect.foldsUnquotedIdent() {
+		return strings.ToLower(tok.Value), nil
+	}
+	return tok.Value, nil
+}
+
+// parseObjectName parses a possibly qualified name such as `db`.`table`
+func (p *ddlParser) parseObjectName() ([]string, error) {
+	var parts []string
+	for {
+		part, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+		if !p.acceptPunct(".") {
+			return parts, nil
+		}
+	}
+}
+
+// skipParens skips a balanced parenthesized group, the current token should be "("
+func (p *ddlParser) skipParens() error {
+	depth := 0
+	for {
+		tok := p.next()
+		switch {
+		case tok.Kind == TokenEOF:
+			return fmt.Errorf("line %d: unbalanced parentheses", tok.Line)
+		case tok.IsPunct("("):
+			depth++
+		case tok.IsPunct(")"):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// parseExpr consumes a single operand expression such as 'a', -1, CURRENT_TIMESTAMP(3), now() or (1 + 2)
+// and returns its source text
+func (p *ddlParser) parseExpr() (string, error) {
+	start := p.peek()
+	if p.peek().IsPunct("-") || p.peek().IsPunct("+") {
+		p.next()
+	}
+
+	tok := p.peek()
+	switch {
+	case tok.IsPunct("("):
+		if err := p.skipParens(); err != nil {
+			return "", err
+		}
+	case tok.Kind == TokenIdent || tok.Kind == TokenQuotedIdent:
+		p.next()
+		if p.peek().IsPunct("(") {
+			if err := p.skipParens(); err != nil {
+				return "", err
+			}
+		}
+		// the array constructors of PostgreSQL, e.g. ARRAY[1, 2]
+		for depth := 0; p.peek().IsPunct("[") || depth > 0; {
+			switch tok := p.next(); {
+			case tok.Kind == TokenEOF:
+				return "", fmt.Errorf("line %d: unbalanced brackets", tok.Line)
+			case tok.IsPunct("["):
+				depth++
+			case tok.IsPunct("]"):
+				depth--
+			}
+		}
+	case tok.Kind == TokenString || tok.Kind == TokenNumber || tok.Kind == TokenBitString:
+		p.next()
+	default:
+		return "", p.errorf("expected expression")
+	}
+	// the type casts of PostgreSQL, e.g. 'a'::character varying
+	for p.peek().IsPunct(":") && p.peekAt(1).IsPunct(":") {
+		p.next()
+		p.next()
+		if _, err := p.parseDataType(); err != nil {
+			return "", err
+		}
+	}
+	return p.sourceFrom(start), nil
+}
+
+// sourceFrom returns the source text from the start token to the last consumed token
+func (p *ddlParser) sourceFrom(start Token) string {
+	end := p.tokens[p.pos-1].End
+	return string(p.src[start.Pos:end])
+}
+
+// skipElement skips tokens until the "," or ")" which ends the current table element
+func (p *ddlParser) skipElement() error {
+	for {
+		tok := p.peek()
+		switch {
+		case tok.Kind == TokenEOF:
+			return p.errorf("expected \")\"")
+		case tok.IsPunct(",") || tok.IsPunct(")"):
+			return nil
+		case tok.IsPunct("("):
+			if err := p.skipParens(); err != nil {
+				return err
+			}
+		default:
+			p.next()
+		}
+	}
+}
+
+// skipSpec skips tokens until the "," which ends the current specification of ALTER TABLE
+func (p *ddlParser) skipSpec() error {
+	for !p.eof() && !p.peek().IsPunct(",") {
+		if p.peek().IsPunct("(") {
+			if err := p.skipParens(); err != nil {
+				return err
+			}
+			continue
+		}
+		p.next()
+	}
+	return nil
+}
+
+func (p *ddlParser) parseCreateTable() (*CreateTableStmt, error) {
+	if err := p.expectKeyword("CREATE"); err != nil {
+		return nil, err
+	}
+	for p.isAnyKeyword(createTableModifiers) {
+		p.next()
+	}
+	if !p.acceptKeyword("TABLE") {
+		return nil, nil
+	}
+
+	stmt := &CreateTableStmt{}
+	stmt.IfNotExists = p.acceptKeyword("IF", "NOT", "EXISTS")
+	name, err := p.parseObjectName()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Table = name[len(name)-1]
+	if len(name) > 1 {
+		stmt.Schema = name[len(name)-2]
+	}
+	// CREATE TABLE ... LIKE and CREATE TABLE ... AS SELECT carry no column definitions
+	if !p.acceptPunct("(") {
+		return nil, nil
+	}
+
+	for {
+		if err := p.parseTableElement(stmt); err != nil {
+			return nil, err
+		}
+		if p.acceptPunct(",") {
+			continue
+		}
+		if err := p.expectPunct(")"); err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	stmt.Options, err = p.parseTableOptions()
+	if err != nil {
+		return nil, err
+	}
+	if p.opts.Dialect == SQLite {
+		sqliteRowIDAlias(stmt)
+	}
+	return stmt, nil
+}
+
+// createdObject looks ahead for the kind of object created by the CREATE statement, e.g. TABLE or VIEW,
+// clauses such as ALGORITHM=MERGE DEFINER=`root`@`%` SQL SECURITY DEFINER before the kind are skipped
+func (p *ddlParser) createdObject() string {
+	for i := p.pos; i < len(p.tokens); i++ {
+		for _, object := range createdObjects {
+			if p.tokens[i].IsKeyword(object) {
+				return object
+			}
+		}
+	}
+	return ""
+}
+
+// parseCreateView parses the name of CREATE [OR REPLACE] [ALGORITHM=...] [DEFINER=...] [SQL SECURITY ...] VIEW name
+func (p *ddlParser) parseCreateView() (*CreateViewStmt, error) {
+	for !p.eof() && !p.isKeyword("VIEW") {
+		p.next()
+	}
+	if err := p.expectKeyword("VIEW"); err != nil {
+		return nil, err
+	}
+	p.acceptKeyword("IF", "NOT", "EXISTS")
+	name, err := p.parseObjectName()
+	if err != nil {
+		return nil, err
+	}
+	return &CreateViewStmt{View: name[len(name)-1]}, nil
+}
+
+func (p *ddlParser) parseTableElement(stmt *CreateTableStmt) error {
+	if p.peek().Kind == TokenIdent && p.isAnyKeyword(tableConstraintKeywords) {
+		var name string
+		if p.acceptKeyword("CONSTRAINT") && !p.isAnyKeyword(tableConstraintKeywords) {
+			var err error
+			if name, err = p.parseIdent(); err != nil {
+				return err
+			}
+		}
+		if p.acceptKeyword("FOREIGN", "KEY") {
+			foreignKey, err := p.parseForeignKey(name)
+			if err != nil {
+				return err
+			}
+			stmt.ForeignKeys = append(stmt.ForeignKeys, foreignKey)
+			return p.skipElement()
+		}
+		constraint, err := p.parseConstraint(name)
+		if err != nil {
+			return err
+		}
+		if constraint != nil {
+			stmt.Constraints = append(stmt.Constraints, constraint)
+		}
+		return p.skipElement()
+	}
+	column, err := p.parseColumnDef()
+	if err != nil {
+		return err
+	}
+	stmt.Columns = append(stmt.Columns, column)
+	return nil
+}
+
+// parseConstraint parses the head of a table level key constraint named name, nil is
+// returned for constraints which are not modeled, e.g. CHECK
+func (p *ddlParser) parseConstraint(name string) (*ConstraintDef, error) {
+	constraint := &ConstraintDef{Name: name}
+
+	switch tok := p.next(); {
+	case tok.IsKeyword("PRIMARY"):
+		if err := p.expectKeyword("KEY"); err != nil {
+			return nil, err
+		}
+		constraint.Kind = IndexPrimary
+	case tok.IsKeyword("UNIQUE"):
+		constraint.Kind = IndexUnique
+	case tok.IsKeyword("KEY"), tok.IsKeyword("INDEX"):
+		constraint.Kind = IndexNormal
+	case tok.IsKeyword("FULLTEXT"):
+		constraint.Kind = IndexFulltext
+	case tok.IsKeyword("SPATIAL"):
+		constraint.Kind = IndexSpatial
+	default:
+		return nil, nil
+	}
+	if constraint.Kind != IndexPrimary && constraint.Kind != IndexNormal && !p.acceptKeyword("KEY") {
+		p.acceptKeyword("INDEX")
+	}
+	if !p.acceptKeyword("CLUSTERED") {
+		p.acceptKeyword("NONCLUSTERED")
+	}
+	// the optional index name, a single name without parentheses is the column of PRIMARY KEY `id`
+	if tok := p.peek(); (tok.Kind == TokenIdent || tok.Kind == TokenQuotedIdent) && !tok.IsKeyword("USING") {
+		name, _ := p.parseIdent()
+		if !p.peek().IsPunct("(") && !p.peek().IsKeyword("USING") {
+			constraint.Columns = []string{name}
+			return constraint, nil
+		}
+		constraint.Name = name
+	}
+	if p.acceptKeyword("USING") {
+		p.next()
+	}
+
+	columns, err := p.parseKeyParts()
+	if err != nil {
+		return nil, err
+	}
+	constraint.Columns = columns
+	return constraint, nil
+}
+
+// parseForeignKey parses the column list and the references of FOREIGN KEY [name] (`a`) REFERENCES `t` (`id`)
+func (p *ddlParser) parseForeignKey(name string) (*ForeignKeyDef, error) {
+	if !p.peek().IsPunct("(") {
+		if _, err := p.parseIdent(); err != nil {
+			return nil, err
+		}
+	}
+	columns, err := p.parseKeyParts()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expectKeyword("REFERENCES"); err != nil {
+		return nil, err
+	}
+	foreignKey, err := p.parseReferences()
+	if err != nil {
+		return nil, err
+	}
+	foreignKey.Name = name
+	foreignKey.Columns = columns
+	return foreignKey, nil
+}
+
+// parseReferences parses the part after REFERENCES, i.e. `t` [(`id`)] [MATCH ...] [ON DELETE ...] [ON UPDATE ...]
+func (p *ddlParser) parseReferences() (*ForeignKeyDef, error) {
+	name, err := p.parseObjectName()
+	if err != nil {
+		return nil, err
+	}
+	foreignKey := &ForeignKeyDef{RefTable: name[len(name)-1]}
+	if p.peek().IsPunct("(") {
+		if foreignKey.RefColumns, err = p.parseKeyParts(); err != nil {
+			return nil, err
+		}
+	}
+	if p.acceptKeyword("MATCH") {
+		p.next()
+	}
+	for p.acceptKeyword("ON") {
+		switch {
+		case p.acceptKeyword("DELETE"):
+			foreignKey.OnDelete = p.parseReferenceAction()
+		case p.acceptKeyword("UPDATE"):
+			foreignKey.OnUpdate = p.parseReferenceAction()
+		default:
+			return nil, p.errorf("expected DELETE or UPDATE")
+		}
+	}
+	return foreignKey, nil
+}
+
+// parseReferenceAction parses one of RESTRICT, CASCADE, SET NULL, SET DEFAULT and NO ACTION
+func (p *ddlParser) parseReferenceAction() string {
+	action := strings.ToUpper(p.next().Value)
+	if action == "SET" || action == "NO" {
+		action += " " + strings.ToUpper(p.next().Value)
+	}
+	return action
+}
+
+// parseKeyParts parses the column list of a key such as (`a`, `b`(10) DESC), expressions are skipped
+func (p *ddlParser) parseKeyParts() ([]string, error) {
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	var columns []string
+	for {
+		if p.peek().IsPunct("(") {
+			if err := p.skipParens(); err != nil {
+				return nil, err
+			}
+		} else {
+			column, err := p.parseIdent()
+			if err != nil {
+				return nil, err
+			}
+			// a prefix length such as `name`(10), otherwise the identifier is a function of an expression
+			if p.peek().IsPunct("(") && p.peekAt(1).Kind != TokenNumber {
+				column = ""
+			}
+			if column != "" {
+				columns = append(columns, column)
+			}
+		}
+		// the length, order, collation or operator class of the key part
+		for !p.eof() && !p.peek().IsPunct(",") && !p.peek().IsPunct(")") {
+			if p.peek().IsPunct("(") {
+				if err := p.skipParens(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			p.next()
+		}
+		if p.acceptPunct(",") {
+			continue
+		}
+		if err := p.expectPunct(")"); err != nil {
+			return nil, err
+		}
+		return columns, nil
+	}
+}
+
+func (p *ddlParser) parseColumnDef() (*ColumnDef, error) {
+	name, err := p.parseIdent()
+	if err != nil {
+		return nil, err
+	}
+	column := &ColumnDef{Name: name}
+	tok := p.peek()
+	if tok.Kind == TokenIdent && !p.isAnyKeyword(columnConstraintKeywords) ||
+		tok.Kind == TokenQuotedIdent && p.opts.Dialect.quotedTypes() {
+		if column.Type, err = p.parseDataType(); err != nil {
+			return nil, err
+		}
+		if p.opts.Dialect.isAutoIncrementType(column.Type.Name) {
+			column.AutoIncrement = true
+			column.NotNull = true
+		}
+	} else if p.opts.Dialect == SQLite {
+		// sqlite columns may be declared without a type
+		column.Type = &DataType{}
+	}
+
+	for {
+		tok := p.peek()
+		switch {
+		// the column definitions of ALTER TABLE may end the statement or be followed by FIRST or AFTER
+		case p.eof() || tok.IsPunct(",") || tok.IsPunct(")") || tok.IsKeyword("FIRST") || tok.IsKeyword("AFTER"):
+			return column, nil
+		case tok.IsPunct("("):
+			if err := p.skipParens(); err != nil {
+				return nil, err
+			}
+		default:
+			if err := p.parseColumnOption(column); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func (p *ddlParser) parseColumnOption(column *ColumnDef) error {
+	var err error
+	switch tok := p.next(); {
+	case tok.IsKeyword("COMMENT"):
+		comment := p.next()
+		if comment.Kind != TokenString {
+			return fmt.Errorf("line %d: expected comment string, got %s", comment.Line, comment)
+		}
+		column.Comment = comment.Value
+	case tok.IsKeyword("NOT") && p.acceptKeyword("NULL"):
+		column.NotNull = true
+	case tok.IsKeyword("NOT") && (p.acceptKeyword("FOR", "REPLICATION") || p.acceptKeyword("DEFERRABLE") ||
+		p.acceptKeyword("ENFORCED")):
+	case tok.IsKeyword("NULL"):
+		column.NotNull = false
+	case tok.IsKeyword("DEFAULT"):
+		var expr string
+		if expr, err = p.parseExpr(); err != nil {
+			return err
+		}
+		column.Default = &expr
+		if isSequenceDefault(expr) {
+			column.AutoIncrement = true
+		}
+	case tok.IsKeyword("ON") && p.acceptKeyword("UPDATE"):
+		column.OnUpdate, err = p.parseExpr()
+	case tok.IsKeyword("ON") && p.acceptKeyword("CONFLICT"),
+		tok.IsKeyword("COLUMN_FORMAT"), tok.IsKeyword("STORAGE"), tok.IsKeyword("SRID"),
+		tok.IsKeyword("ENGINE_ATTRIBUTE"), tok.IsKeyword("SECONDARY_ENGINE_ATTRIBUTE"):
+		// the option and its value, e.g. ON CONFLICT REPLACE of sqlite or COLUMN_FORMAT FIXED of mysql
+		p.acceptPunct("=")
+		p.next()
+	case tok.IsKeyword("CHECK"), tok.IsKeyword("AS"), tok.IsKeyword("MASKED"), tok.IsKeyword("WITH"):
+		// the check, the computed expression or the options in the parentheses are skipped
+		p.acceptKeyword("WITH")
+	case tok.IsKeyword("NO") && p.acceptKeyword("INHERIT"), tok.IsKeyword("SERIAL") && p.acceptKeyword("DEFAULT", "VALUE"),
+		tok.IsKeyword("INITIALLY") && (p.acceptKeyword("DEFERRED") || p.acceptKeyword("IMMEDIATE")),
+		tok.IsAnyKeyword(ignoredColumnOptions):
+	case tok.IsKeyword("AUTO_INCREMENT"), tok.IsKeyword("AUTOINCREMENT"):
+		column.AutoIncrement = true
+	case tok.IsKeyword("UNSIGNED"):
+		column.Unsigned = true
+	case tok.IsKeyword("ZEROFILL"):
+		column.Zerofill = true
+	case tok.IsKeyword("CHARSET"), tok.IsKeyword("CHARACTER") && p.acceptKeyword("SET"):
+		column.Charset = p.next().Value
+	case tok.IsKeyword("COLLATE"):
+		column.Collation = p.next().Value
+	case tok.IsKeyword("PRIMARY") && p.acceptKeyword("KEY"), tok.IsKeyword("KEY"):
+		column.PrimaryKey = true
+	case tok.IsKeyword("UNIQUE"):
+		p.acceptKeyword("KEY")
+		column.Unique = true
+	case tok.IsKeyword("REFERENCES"):
+		column.References, err = p.parseReferences()
+	case tok.IsKeyword("CONSTRAINT"):
+		_, err = p.parseIdent()
+	case tok.IsKeyword("IDENTITY"):
+		// IDENTITY [(seed, increment)] of SQL Server
+		column.AutoIncrement = true
+		if p.peek().IsPunct("(") {
+			err = p.skipParens()
+		}
+	case tok.IsKeyword("GENERATED"):
+		if !p.acceptKeyword("ALWAYS") {
+			p.acceptKeyword("BY", "DEFAULT")
+		}
+		if err = p.expectKeyword("AS"); err != nil {
+			return err
+		}
+		// GENERATED ... AS IDENTITY [(sequence options)], otherwise the generated expression is skipped
+		if p.acceptKeyword("IDENTITY") {
+			column.AutoIncrement = true
+			column.NotNull = true
+		}
+		if p.peek().IsPunct("(") {
+			err = p.skipParens()
+		}
+	default:
+		return fmt.Errorf("line %d: unknown column option %s of column %s", tok.Line, tok, column.Name)
+	}
+	return err
+}
+
+// parseDataType parses a type such as int(11), double precision, character varying(64),
+// timestamp(3) with time zone or text[]
+func (p *ddlParser) parseDataType() (*DataType, error) {
+	words := []string{p.next().Value}
+	if p.isAnyKeyword([]string{"PRECISION", "VARYING"}) {
+		words = append(words, p.next().Value)
+	}
+	for p.opts.Dialect.multiWordTypes() && p.peek().Kind == TokenIdent && !p.isAnyKeyword(columnConstraintKeywords) {
+		words = append(words, p.next().Value)
+	}
+	dataType := &DataType{}
+	if p.acceptPunct("(") {
+	args:
+		for {
+			tok := p.next()
+			switch {
+			case tok.Kind == TokenEOF:
+				return nil, fmt.Errorf("line %d: unterminated arguments of type %s", tok.Line, words[0])
+			case tok.IsPunct(")"):
+				break args
+			case tok.IsPunct(","):
+			default:
+				dataType.Args = append(dataType.Args, tok.Value)
+			}
+		}
+	}
+	for _, zone := range [][]string{{"WITH", "TIME", "ZONE"}, {"WITHOUT", "TIME", "ZONE"}} {
+		if p.acceptKeyword(zone...) {
+			words = append(words, strings.ToLower(strings.Join(zone, " ")))
+		}
+	}
+	dataType.Name = strings.Join(words, " ")
+
+	for p.peek().IsPunct("[") {
+		for !p.eof() && !p.next().IsPunct("]") {
+		}
+		dataType.Array = true
+	}
+	if p.acceptKeyword("ARRAY") {
+		dataType.Array = true
+	}
+	return dataType, nil
+}
+
+// parseComment parses COMMENT ON {TABLE | COLUMN} name IS 'comment', nil is returned for other objects
+func (p *ddlParser) parseComment() (*CommentStmt, error) {
+	if err := p.expectKeyword("COMMENT", "ON"); err != nil {
+		return nil, err
+	}
+	onColumn := p.acceptKeyword("COLUMN")
+	if !onColumn && !p.acceptKeyword("TABLE") {
+		return nil, nil
+	}
+	name, err := p.parseObjectName()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expectKeyword("IS"); err != nil {
+		return nil, err
+	}
+	comment := p.next()
+	if comment.Kind != TokenString && !comment.IsKeyword("NULL") {
+		return nil, fmt.Errorf("line %d: expected comment string, got %s", comment.Line, comment)
+	}
+
+	stmt := &CommentStmt{Table: name[len(name)-1], Comment: comment.Value}
+	if comment.Kind != TokenString {
+		stmt.Comment = ""
+	}
+	if onColumn {
+		if len(name) < 2 {
+			return nil, fmt.Errorf("line %d: expected table.column of comment", comment.Line)
+		}
+		stmt.Table, stmt.Column = name[len(name)-2], name[len(name)-1]
+	}
+	return stmt, nil
+}
+
+// parseTableOptions parses the options following the column definitions, e.g. ENGINE=InnoDB COMMENT='test'
+func (p *ddlParser) parseTableOptions() ([]*TableOption, error) {
+	var options []*TableOption
+	for !p.eof() {
+		tok := p.next()
+		if tok.Kind != TokenIdent {
+			continue
+		}
+		name := strings.ToUpper(tok.Value)
+		if name == "DEFAULT" && p.peek().Kind == TokenIdent {
+			name = strings.ToUpper(p.next().Value)
+		}
+		if name == "CHARACTER" && p.acceptKeyword("SET") {
+			name = "CHARACTER SET"
+		}
+		if name == "WITHOUT" && p.acceptKeyword("ROWID") {
+			options = append(options, &TableOption{Name: "WITHOUT ROWID"})
+			continue
+		}
+		// partitioning is not part of the table structure
+		if name == "PARTITION" {
+			break
+		}
+		p.acceptPunct("=")
+
+		option := &TableOption{Name: name}
+		switch next := p.peek(); {
+		case next.IsPunct("("):
+			if err := p.skipParens(); err != nil {
+				return nil, err
+			}
+		case next.Kind == TokenIdent, next.Kind == TokenQuotedIdent, next.Kind == TokenString, next.Kind == TokenNumber,
+			next.Kind == TokenBitString:
+			option.Value = p.next().Value
+		}
+		options = append(options, option)
+	}
+	return options, nil
+}
+
+// parseCreateIndex parses CREATE [UNIQUE | FULLTEXT | SPATIAL] INDEX [name] ON table [USING method] (key parts)
+func (p *ddlParser) parseCreateIndex() (*CreateIndexStmt, error) {
+	index := &ConstraintDef{Kind: IndexNormal}
+	for !p.eof() && !p.isKeyword("INDEX") {
+		switch tok := p.next(); {
+		case tok.IsKeyword("UNIQUE"):
+			index.Kind = IndexUnique
+		case tok.IsKeyword("FULLTEXT"):
+			index.Kind = IndexFulltext
+		case tok.IsKeyword("SPATIAL"):
+			index.Kind = IndexSpatial
+		}
+	}
+	if err := p.expectKeyword("INDEX"); err != nil {
+		return nil, err
+	}
+	p.acceptKeyword("CONCURRENTLY")
+	p.acceptKeyword("IF", "NOT", "EXISTS")
+	if !p.isKeyword("ON") {
+		name, err := p.parseObjectName()
+		if err != nil {
+			return nil, err
+		}
+		index.Name = name[len(name)-1]
+	}
+	if p.acceptKeyword("USING") {
+		p.next()
+	}
+	if err := p.expectKeyword("ON"); err != nil {
+		return nil, err
+	}
+	p.acceptKeyword("ONLY")
+	table, err := p.parseObjectName()
+	if err != nil {
+		return nil, err
+	}
+	if p.acceptKeyword("USING") {
+		p.next()
+	}
+	if index.Columns, err = p.parseKeyParts(); err != nil {
+		return nil, err
+	}
+	return &CreateIndexStmt{Table: table[len(table)-1], Index: index}, nil
+}
+
+// parseAlterTable parses ALTER TABLE name spec [, spec]..., nil is returned when other objects are altered
+func (p *ddlParser) parseAlterTable() (*AlterTableStmt, error) {
+	if err := p.expectKeyword("ALTER"); err != nil {
+		return nil, err
+	}
+	for p.isAnyKeyword([]string{"ONLINE", "OFFLINE", "IGNORE"}) {
+		p.next()
+	}
+	if !p.acceptKeyword("TABLE") {
+		return nil, nil
+	}
+	p.acceptKeyword("IF", "EXISTS")
+	p.acceptKeyword("ONLY")
+	name, err := p.parseObjectName()
+	if err != nil {
+		return nil, err
+	}
+
+	stmt := &AlterTableStmt{Table: name[len(name)-1]}
+	var last AlterAction
+	for !p.eof() {
+		count := len(stmt.Specs)
+		switch {
+		case p.acceptKeyword("ADD"):
+			err = p.parseAlterAdd(stmt)
+		case p.acceptKeyword("DROP"):
+			err = p.parseAlterDrop(stmt)
+		case p.acceptKeyword("MODIFY"):
+			err = p.parseAlterModify(stmt, false)
+		case p.acceptKeyword("CHANGE"):
+			err = p.parseAlterModify(stmt, true)
+		case p.acceptKeyword("ALTER"):
+			err = p.parseAlterColumn(stmt)
+		case p.acceptKeyword("RENAME"):
+			err = p.parseAlterRename(stmt)
+		// SQL Server adds or drops several columns by one ADD or DROP COLUMN, e.g. ADD a int, b int
+		case p.opts.Dialect == SQLServer && last == AlterAddColumn:
+			err = p.parseAlterAdd(stmt)
+		case p.opts.Dialect == SQLServer && last == AlterDropColumn:
+			err = p.parseAlterDrop(stmt)
+		}
+		if err != nil {
+			return nil, err
+		}
+		last = ""
+		if len(stmt.Specs) > count {
+			last = stmt.Specs[len(stmt.Specs)-1].Action
+		}
+		if err = p.skipSpec(); err != nil {
+			return nil, err
+		}
+		if !p.acceptPunct(",") {
+			break
+		}
+	}
+	return stmt, nil
+}
+
+// parseAlterAdd parses the part after ADD, i.e. [COLUMN] column, [COLUMN] (column, ...), a key or a foreign key
+func (p *ddlParser) parseAlterAdd(stmt *AlterTableStmt) error {
+	if p.peek().Kind == TokenIdent && p.isAnyKeyword(tableConstraintKeywords) {
+		var name string
+		if p.acceptKeyword("CONSTRAINT") && !p.isAnyKeyword(tableConstraintKeywords) {
+			var err error
+			if name, err = p.parseIdent(); err != nil {
+				return err
+			}
+		}
+		switch {
+		case p.acceptKeyword("FOREIGN", "KEY"):
+			foreignKey, err := p.parseForeignKey(name)
+			if err != nil {
+				return err
+			}
+			stmt.Specs = append(stmt.Specs, &AlterSpec{Action: AlterAddForeignKey, ForeignKey: foreignKey})
+			return nil
+		case p.acceptKeyword("DEFAULT"):
+			// the default constraint of SQL Server, i.e. DEFAULT expr FOR column
+			expr, err := p.parseExpr()
+			if err != nil {
+				return err
+			}
+			if err = p.expectKeyword("FOR"); err != nil {
+				return err
+			}
+			column, err := p.parseIdent()
+			if err != nil {
+				return err
+			}
+			stmt.Specs = append(stmt.Specs, &AlterSpec{Action: AlterSetDefault, Name: column, Default: &expr})
+			return nil
+		}
+		constraint, err := p.parseConstraint(name)
+		if err != nil || constraint == nil {
+			return err
+		}
+		stmt.Specs = append(stmt.Specs, &AlterSpec{Action: AlterAddIndex, Constraint: constraint})
+		return nil
+	}
+
+	p.acceptKeyword("COLUMN")
+	p.acceptKeyword("IF", "NOT", "EXISTS")
+	if !p.acceptPunct("(") {
+		column, err := p.parseColumnDef()
+		if err != nil {
+			return err
+		}
+		spec := &AlterSpec{Action: AlterAddColumn, Name: column.Name, Column: column}
+		stmt.Specs = append(stmt.Specs, spec)
+		return p.parsePosition(spec)
+	}
+	for {
+		column, err := p.parseColumnDef()
+		if err != nil {
+			return err
+		}
+		stmt.Specs = append(stmt.Specs, &AlterSpec{Action: AlterAddColumn, Name: column.Name, Column: column})
+		if !p.acceptPunct(",") {
+			return p.expectPunct(")")
+		}
+	}
+}
+
+// parsePosition parses the optional FIRST or AFTER column of ADD, MODIFY and CHANGE COLUMN
+func (p *ddlParser) parsePosition(spec *AlterSpec) error {
+	if p.acceptKeyword("FIRST") {
+		spec.First = true
+		return nil
+	}
+	if !p.acceptKeyword("AFTER") {
+		return nil
+	}
+	var err error
+	spec.After, err = p.parseIdent()
+	return err
+}
+
+// parseAlterDrop parses the part after DROP, i.e. [COLUMN] name, a key, a foreign key or a constraint
+func (p *ddlParser) parseAlterDrop(stmt *AlterTableStmt) error {
+	spec := &AlterSpec{}
+	switch {
+	case p.acceptKeyword("PRIMARY", "KEY"):
+		stmt.Specs = append(stmt.Specs, &AlterSpec{Action: AlterDropPrimaryKey})
+		return nil
+	case p.acceptKeyword("INDEX"), p.acceptKeyword("KEY"):
+		spec.Action = AlterDropIndex
+	case p.acceptKeyword("FOREIGN", "KEY"):
+		spec.Action = AlterDropForeignKey
+	case p.acceptKeyword("CONSTRAINT"):
+		spec.Action = AlterDropConstraint
+	case p.isAnyKeyword([]string{"CHECK", "PARTITION", "DEFAULT", "PERIOD"}):
+		return nil
+	default:
+		p.acceptKeyword("COLUMN")
+		spec.Action = AlterDropColumn
+	}
+	p.acceptKeyword("IF", "EXISTS")
+
+	var err error
+	if spec.Name, err = p.parseIdent(); err != nil {
+		return err
+	}
+	stmt.Specs = append(stmt.Specs, spec)
+	return nil
+}
+
+// parseAlterModify parses MODIFY [COLUMN] column [FIRST | AFTER name] and CHANGE [COLUMN] name column [FIRST | AFTER name]
+func (p *ddlParser) parseAlterModify(stmt *AlterTableStmt, change bool) error {
+	p.acceptKeyword("COLUMN")
+	var (
+		name string
+		err  error
+	)
+	if change {
+		if name, err = p.parseIdent(); err != nil {
+			return err
+		}
+	}
+	column, err := p.parseColumnDef()
+	if err != nil {
+		return err
+	}
+	if !change {
+		name = column.Name
+	}
+	spec := &AlterSpec{Action: AlterModifyColumn, Name: name, Column: column}
+	stmt.Specs = append(stmt.Specs, spec)
+	return p.parsePosition(spec)
+}
+
+// parseAlterColumn parses ALTER [COLUMN] name {SET DEFAULT expr | DROP DEFAULT | SET NOT NULL | DROP NOT NULL |
+// [SET DATA] TYPE type}, SQL Server redefines the whole column by ALTER COLUMN name type
+func (p *ddlParser) parseAlterColumn(stmt *AlterTableStmt) error {
+	p.acceptKeyword("COLUMN")
+	start := p.pos
+	name, err := p.parseIdent()
+	if err != nil {
+		return err
+	}
+
+	spec := &AlterSpec{Name: name}
+	switch {
+	case p.acceptKeyword("SET", "DEFAULT"):
+		spec.Action = AlterSetDefault
+		var expr string
+		if expr, err = p.parseExpr(); err != nil {
+			return err
+		}
+		spec.Default = &expr
+	case p.acceptKeyword("DROP", "DEFAULT"):
+		spec.Action = AlterDropDefault
+	case p.acceptKeyword("SET", "NOT", "NULL"):
+		spec.Action = AlterSetNotNull
+	case p.acceptKeyword("DROP", "NOT", "NULL"):
+		spec.Action = AlterDropNotNull
+	case p.acceptKeyword("TYPE"), p.acceptKeyword("SET", "DATA", "TYPE"):
+		spec.Action = AlterSetType
+		if spec.Type, err = p.parseDataType(); err != nil {
+			return err
+		}
+	case p.opts.Dialect == SQLServer:
+		p.pos = start
+		return p.parseAlterModify(stmt, false)
+	default:
+		return nil
+	}
+	stmt.Specs = append(stmt.Specs, spec)
+	return nil
+}
+
+// parseAlterRename parses RENAME [TO | AS] table, RENAME [COLUMN] a TO b and RENAME {INDEX | KEY | CONSTRAINT} a TO b
+func (p *ddlParser) parseAlterRename(stmt *AlterTableStmt) error {
+	spec := &AlterSpec{}
+	switch {
+	case p.acceptKeyword("TO"), p.acceptKeyword("AS"), !p.peekAt(1).IsKeyword("TO") && !p.isAnyKeyword([]string{"COLUMN", "INDEX", "KEY", "CONSTRAINT"}):
+		name, err := p.parseObjectName()
+		if err != nil {
+			return err
+		}
+		stmt.Specs = append(stmt.Specs, &AlterSpec{Action: AlterRenameTable, NewName: name[len(name)-1]})
+		return nil
+	case p.acceptKeyword("INDEX"), p.acceptKeyword("KEY"), p.acceptKeyword("CONSTRAINT"):
+		spec.Action = AlterRenameIndex
+	default:
+		// PostgreSQL allows to omit COLUMN
+		p.acceptKeyword("COLUMN")
+		spec.Action = AlterRenameColumn
+	}
+
+	var err error
+	if spec.Name, err = p.parseIdent(); err != nil {
+		return err
+	}
+	if err = p.expectKeyword("TO"); err != nil {
+		return err
+	}
+	if spec.NewName, err = p.parseIdent(); err != nil {
+		return err
+	}
+	stmt.Specs = append(stmt.Specs, spec)
+	return nil
+}
+
+// parseRenameTable parses RENAME TABLE a TO b [, c TO d]..., nil is returned when other objects are renamed
+func (p *ddlParser) parseRenameTable() (*RenameTableStmt, error) {
+	if err := p.expectKeyword("RENAME"); err != nil {
+		return nil, err
+	}
+	if !p.acceptKeyword("TABLE") {
+		return nil, nil
+	}
+	stmt := &RenameTableStmt{}
+	for {
+		old, err := p.parseObjectName()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expectKeyword("TO"); err != nil {
+			return nil, err
+		}
+		name, err := p.parseObjectName()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Renames = append(stmt.Renames, &TableRename{Old: old[len(old)-1], New: name[len(name)-1]})
+		if !p.acceptPunct(",") {
+			return stmt, nil
+		}
+	}
+}
+
+// parseDropTable parses DROP [TEMPORARY] TABLE [IF EXISTS] name [, name]..., nil is returned when other objects are dropped
+func (p *ddlParser) parseDropTable() (*DropTableStmt, error) {
+	if err := p.expectKeyword("DROP"); err != nil {
+		return nil, err
+	}
+	p.acceptKeyword("TEMPORARY")
+	if !p.acceptKeyword("TABLE") {
+		return nil, nil
+	}
+	p.acceptKeyword("IF", "EXISTS")
+	stmt := &DropTableStmt{}
+	for {
+		name, err := p.parseObjectName()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Tables = append(stmt.Tables, name[len(name)-1])
+		if !p.acceptPunct(",") {
+			return stmt, nil
+		}
+	}
+}
+
+// parseDropIndex parses DROP INDEX [CONCURRENTLY] [IF EXISTS] name [ON table], SQL Server also names
+// the index by table.name
+func (p *ddlParser) parseDropIndex() (*DropIndexStmt, error) {
+	if err := p.expectKeyword("DROP", "INDEX"); err != nil {
+		return nil, err
+	}
+	p.acceptKeyword("CONCURRENTLY")
+	p.acceptKeyword("IF", "EXISTS")
+	name, err := p.parseObjectName()
+	if err != nil {
+		return nil, err
+	}
+	stmt := &DropIndexStmt{Name: name[len(name)-1]}
+	if p.opts.Dialect == SQLServer && len(name) > 1 {
+		stmt.Table = name[len(name)-2]
+	}
+	if p.acceptKeyword("ON") {
+		table, err := p.parseObjectName()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Table = table[len(table)-1]
+	}
+	return stmt, nil
+}
@@ -0,0 +1,355 @@
+package sqlconverter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCreateTable(t *testing.T) {
+	inputs := []string{
+		"CREATE TABLE users (id bigint NOT NULL, name varchar(64) DEFAULT 'a,b' COMMENT 'user name')",
+		"CREATE TEMPORARY TABLE IF NOT EXISTS `db`.`t` (`amount` decimal(10,2) DEFAULT (round(1.5, 0)), " +
+			"`kind` enum('a','b') CHECK (kind IN ('a', 'b')), CONSTRAINT `chk` CHECK ((amount > 0))) " +
+			"ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='test'",
+		"create table t (a int, PRIMARY KEY (a), KEY idx_a (a(10)), UNIQUE KEY (a), FOREIGN KEY (a) REFERENCES b (id))",
+	}
+	expecteds := []*CreateTableStmt{
+		{
+			Table: "users",
+			Columns: []*ColumnDef{
+				{Name: "id", Type: &DataType{Name: "bigint"}, NotNull: true},
+				{Name: "name", Type: &DataType{Name: "varchar", Args: []string{"64"}}, Comment: "user name", Default: stringPtr("'a,b'")},
+			},
+		},
+		{
+			Schema:      "db",
+			Table:       "t",
+			IfNotExists: true,
+			Columns: []*ColumnDef{
+				{Name: "amount", Type: &DataType{Name: "decimal", Args: []string{"10", "2"}}, Default: stringPtr("(round(1.5, 0))")},
+				{Name: "kind", Type: &DataType{Name: "enum", Args: []string{"a", "b"}}},
+			},
+			Options: []*TableOption{
+				{Name: "ENGINE", Value: "InnoDB"},
+				{Name: "CHARSET", Value: "utf8mb4"},
+				{Name: "COMMENT", Value: "test"},
+			},
+		},
+		{
+			Table: "t",
+			Columns: []*ColumnDef{
+				{Name: "a", Type: &DataType{Name: "int"}},
+			},
+			Constraints: []*ConstraintDef{
+				{Kind: IndexPrimary, Columns: []string{"a"}},
+				{Kind: IndexNormal, Name: "idx_a", Columns: []string{"a"}},
+				{Kind: IndexUnique, Columns: []string{"a"}},
+			},
+			ForeignKeys: []*ForeignKeyDef{
+				{Columns: []string{"a"}, RefTable: "b", RefColumns: []string{"id"}},
+			},
+		},
+	}
+
+	for i, input := range inputs {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			actual, err := parseCreateTable(input, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, expecteds[i], actual)
+		})
+	}
+}
+
+func TestParseConstraint(t *testing.T) {
+	inputs := []string{
+		"CREATE TABLE t (a int, b int, PRIMARY KEY `a`)",
+		"CREATE TABLE t (a int, b int, CONSTRAINT pk PRIMARY KEY USING BTREE (a, b))",
+		"CREATE TABLE t (a int, b int, CONSTRAINT `uk_ab` UNIQUE (`a`, `b` DESC))",
+		"CREATE TABLE t (a int, b int, UNIQUE INDEX uk_b (b) COMMENT 'unique b')",
+		"CREATE TABLE t (a int, b text, FULLTEXT KEY ft_b (b), INDEX idx_expr ((a + 1), b))",
+		"CREATE TABLE t (a int, b int, CONSTRAINT CHECK (a > b))",
+	}
+	expecteds := [][]*ConstraintDef{
+		{{Kind: IndexPrimary, Columns: []string{"a"}}},
+		{{Kind: IndexPrimary, Name: "pk", Columns: []string{"a", "b"}}},
+		{{Kind: IndexUnique, Name: "uk_ab", Columns: []string{"a", "b"}}},
+		{{Kind: IndexUnique, Name: "uk_b", Columns: []string{"b"}}},
+		{
+			{Kind: IndexFulltext, Name: "ft_b", Columns: []string{"b"}},
+			{Kind: IndexNormal, Name: "idx_expr", Columns: []string{"b"}},
+		},
+		nil,
+	}
+
+	for i, input := range inputs {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			actual, err := parseCreateTable(input, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, expecteds[i], actual.Constraints)
+		})
+	}
+}
+
+func TestParseForeignKey(t *testing.T) {
+	input := "CREATE TABLE orders (id int, user_id int REFERENCES users, shop_id int, " +
+		"CONSTRAINT `fk_shop` FOREIGN KEY `idx_shop` (`shop_id`) REFERENCES `db`.`shops` (`id`) " +
+		"MATCH FULL ON DELETE SET NULL ON UPDATE CASCADE)"
+	actual, err := parseCreateTable(input, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, &ForeignKeyDef{RefTable: "users"}, actual.Columns[1].References)
+	assert.Equal(t, []*ForeignKeyDef{
+		{
+			Name:       "fk_shop",
+			Columns:    []string{"shop_id"},
+			RefTable:   "shops",
+			RefColumns: []string{"id"},
+			OnDelete:   "SET NULL",
+			OnUpdate:   "CASCADE",
+		},
+	}, actual.ForeignKeys)
+}
+
+func TestParseColumnOptions(t *testing.T) {
+	input := "CREATE TABLE t (" +
+		"`id` BIGINT(20) UNSIGNED ZEROFILL NOT NULL AUTO_INCREMENT PRIMARY KEY, " +
+		"`name` VARCHAR(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NULL DEFAULT NULL, " +
+		"`balance` DECIMAL(10,2) NOT NULL DEFAULT -1.5, " +
+		"`updated_at` DATETIME(3) DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3), " +
+		"`deleted` BIT(1) NOT NULL DEFAULT b'0', `flags` BINARY(1) DEFAULT x'0F')"
+	expected := []*ColumnDef{
+		{
+			Name:          "id",
+			Type:          &DataType{Name: "BIGINT", Args: []string{"20"}},
+			NotNull:       true,
+			AutoIncrement: true,
+			Unsigned:      true,
+			Zerofill:      true,
+			PrimaryKey:    true,
+		},
+		{
+			Name:      "name",
+			Type:      &DataType{Name: "VARCHAR", Args: []string{"32"}},
+			Default:   stringPtr("NULL"),
+			Charset:   "utf8mb4",
+			Collation: "utf8mb4_bin",
+		},
+		{
+			Name:    "balance",
+			Type:    &DataType{Name: "DECIMAL", Args: []string{"10", "2"}},
+			NotNull: true,
+			Default: stringPtr("-1.5"),
+		},
+		{
+			Name:     "updated_at",
+			Type:     &DataType{Name: "DATETIME", Args: []string{"3"}},
+			Default:  stringPtr("CURRENT_TIMESTAMP(3)"),
+			OnUpdate: "CURRENT_TIMESTAMP(3)",
+		},
+		{
+			Name:    "deleted",
+			Type:    &DataType{Name: "BIT", Args: []string{"1"}},
+			NotNull: true,
+			Default: stringPtr("b'0'"),
+		},
+		{
+			Name:    "flags",
+			Type:    &DataType{Name: "BINARY", Args: []string{"1"}},
+			Default: stringPtr("x'0F'"),
+		},
+	}
+
+	actual, err := parseCreateTable(input, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, expected, actual.Columns)
+}
+
+func TestParseIgnoredColumnOptions(t *testing.T) {
+	inputs := map[Dialect]string{
+		MySQL: "CREATE TABLE t (a int, b varchar(8) BINARY COLUMN_FORMAT FIXED STORAGE DISK INVISIBLE, " +
+			"c int AS (a + 1) STORED CHECK (c > 0) NOT ENFORCED, d bigint SERIAL DEFAULT VALUE, e point SRID 4326)",
+		PostgreSQL: "CREATE TABLE t (a int CHECK (a > 0) NO INHERIT, b int REFERENCES u (id) DEFERRABLE INITIALLY DEFERRED, " +
+			"c int[] DEFAULT ARRAY[1, 2])",
+		SQLite: "CREATE TABLE t (a INTEGER PRIMARY KEY DESC ON CONFLICT REPLACE, b TEXT NOT NULL ON CONFLICT IGNORE)",
+		SQLServer: "CREATE TABLE t (a int IDENTITY(1, 1) NOT FOR REPLICATION PRIMARY KEY CLUSTERED, b uniqueidentifier ROWGUIDCOL, " +
+			"c varchar(8) MASKED WITH (FUNCTION = 'default()') SPARSE NULL, d AS (a + 1) PERSISTED)",
+	}
+	for dialect, input := range inputs {
+		t.Run(string(dialect), func(t *testing.T) {
+			actual, err := parseCreateTable(input, Options{Dialect: dialect})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.NotEmpty(t, actual.Columns)
+		})
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestParseCreateTableSkipped(t *testing.T) {
+	inputs := []string{
+		"CREATE INDEX idx ON t (a)",
+		"CREATE TABLE t2 LIKE t1",
+		"CREATE TABLE t2 AS SELECT * FROM t1",
+	}
+	for i, input := range inputs {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			actual, err := parseCreateTable(input, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Nil(t, actual)
+		})
+	}
+}
+
+func TestParseCreateTableError(t *testing.T) {
+	inputs := []string{
+		"CREATE TABLE t (a int",
+		"CREATE TABLE t (a int COMMENT 1)",
+		"CREATE TABLE (a int)",
+		"CREATE TABLE t (a int DEFAULT (1)",
+		"CREATE TABLE t (a int NOT NULL UNKNOWN)",
+		"CREATE TABLE t (a int DEFAULT 1 2)",
+	}
+	for i, input := range inputs {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			_, err := parseCreateTable(input, Options{})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseCreateTableCase(t *testing.T) {
+	input := "Create Table `UserInfo` (`UserID` INT Comment 'User ID from SSO', Name Varchar(8) COMMENT 'Full Name')"
+	cases := map[string]Options{
+		"Fold": {FoldIdentCase: true},
+		"Keep": {},
+	}
+	expected := map[string]*CreateTableStmt{
+		"Fold": {
+			Table: "userinfo",
+			Columns: []*ColumnDef{
+				{Name: "userid", Type: &DataType{Name: "INT"}, Comment: "User ID from SSO"},
+				{Name: "name", Type: &DataType{Name: "Varchar", Args: []string{"8"}}, Comment: "Full Name"},
+			},
+		},
+		"Keep": {
+			Table: "UserInfo",
+			Columns: []*ColumnDef{
+				{Name: "UserID", Type: &DataType{Name: "INT"}, Comment: "User ID from SSO"},
+				{Name: "Name", Type: &DataType{Name: "Varchar", Args: []string{"8"}}, Comment: "Full Name"},
+			},
+		},
+	}
+
+	for name, opts := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := parseCreateTable(input, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, expected[name], actual)
+		})
+	}
+}
+
+func TestParseAlterTable(t *testing.T) {
+	inputs := []string{
+		"ALTER TABLE `db`.`users` ADD COLUMN `age` int NOT NULL DEFAULT 0 AFTER `name`, DROP COLUMN `nick`, " +
+			"MODIFY `name` varchar(128) COMMENT 'name', CHANGE COLUMN `mail` `email` varchar(64) FIRST, ENGINE=InnoDB",
+		"ALTER TABLE users ADD (a int, b int), ADD UNIQUE KEY uk_a (a), ADD CONSTRAINT fk_b FOREIGN KEY (b) REFERENCES t (id), " +
+			"DROP INDEX idx_c, DROP PRIMARY KEY, DROP FOREIGN KEY fk_d, RENAME INDEX idx_e TO idx_f, RENAME TO members",
+		"ALTER TABLE ONLY public.users ALTER COLUMN id SET DEFAULT nextval('users_id_seq'::regclass), " +
+			"ALTER name TYPE text USING name::text, ALTER COLUMN age DROP NOT NULL, RENAME a TO b, OWNER TO postgres",
+	}
+	expecteds := []*AlterTableStmt{
+		{
+			Table: "users",
+			Specs: []*AlterSpec{
+				{Action: AlterAddColumn, Name: "age", After: "name",
+					Column: &ColumnDef{Name: "age", Type: &DataType{Name: "int"}, NotNull: true, Default: stringPtr("0")}},
+				{Action: AlterDropColumn, Name: "nick"},
+				{Action: AlterModifyColumn, Name: "name",
+					Column: &ColumnDef{Name: "name", Type: &DataType{Name: "varchar", Args: []string{"128"}}, Comment: "name"}},
+				{Action: AlterModifyColumn, Name: "mail", First: true,
+					Column: &ColumnDef{Name: "email", Type: &DataType{Name: "varchar", Args: []string{"64"}}}},
+			},
+		},
+		{
+			Table: "users",
+			Specs: []*AlterSpec{
+				{Action: AlterAddColumn, Name: "a", Column: &ColumnDef{Name: "a", Type: &DataType{Name: "int"}}},
+				{Action: AlterAddColumn, Name: "b", Column: &ColumnDef{Name: "b", Type: &DataType{Name: "int"}}},
+				{Action: AlterAddIndex, Constraint: &ConstraintDef{Kind: IndexUnique, Name: "uk_a", Columns: []string{"a"}}},
+				{Action: AlterAddForeignKey,
+					ForeignKey: &ForeignKeyDef{Name: "fk_b", Columns: []string{"b"}, RefTable: "t", RefColumns: []string{"id"}}},
+				{Action: AlterDropIndex, Name: "idx_c"},
+				{Action: AlterDropPrimaryKey},
+				{Action: AlterDropForeignKey, Name: "fk_d"},
+				{Action: AlterRenameIndex, Name: "idx_e", NewName: "idx_f"},
+				{Action: AlterRenameTable, NewName: "members"},
+			},
+		},
+		{
+			Table: "users",
+			Specs: []*AlterSpec{
+				{Action: AlterSetDefault, Name: "id", Default: stringPtr("nextval('users_id_seq'::regclass)")},
+				{Action: AlterSetType, Name: "name", Type: &DataType{Name: "text"}},
+				{Action: AlterDropNotNull, Name: "age"},
+				{Action: AlterRenameColumn, Name: "a", NewName: "b"},
+			},
+		},
+	}
+	dialects := []Dialect{MySQL, MySQL, PostgreSQL}
+
+	for i, input := range inputs {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			actual, err := parseStatement(input, Options{Dialect: dialects[i]})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, expecteds[i], actual)
+		})
+	}
+}
+
+func TestParseSchemaChange(t *testing.T) {
+	inputs := []string{
+		"CREATE UNIQUE INDEX IF NOT EXISTS uk_name ON public.users USING btree (lower(name), email)",
+		"RENAME TABLE a TO b, `db`.`c` TO `d`",
+		"DROP TABLE IF EXISTS a, b CASCADE",
+		"DROP INDEX idx_a ON t",
+		"DROP DATABASE shop",
+	}
+	expecteds := []Statement{
+		&CreateIndexStmt{Table: "users", Index: &ConstraintDef{Kind: IndexUnique, Name: "uk_name", Columns: []string{"email"}}},
+		&RenameTableStmt{Renames: []*TableRename{{Old: "a", New: "b"}, {Old: "c", New: "d"}}},
+		&DropTableStmt{Tables: []string{"a", "b"}},
+		&DropIndexStmt{Table: "t", Name: "idx_a"},
+		nil,
+	}
+
+	for i, input := range inputs {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			actual, err := parseStatement(input, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, expecteds[i], actual)
+		})
+	}
+}
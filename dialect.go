@@ -0,0 +1,209 @@
+package sqlconverter
+
+import (
+	"strings"
+)
+
+type Dialect string
+
+const (
+	MySQL      Dialect = "MYSQL"
+	PostgreSQL Dialect = "POSTGRESQL"
+	SQLite     Dialect = "SQLITE"
+	SQLServer  Dialect = "SQLSERVER"
+)
+
+var AllowedDialect = []Dialect{MySQL, PostgreSQL, SQLite, SQLServer}
+
+func (d Dialect) IsAllowed() bool {
+	for _, dialect := range AllowedDialect {
+		if dialect == d {
+			return true
+		}
+	}
+	return false
+}
+
+func (d Dialect) lexerConfig() lexerConfig {
+	switch d {
+	case PostgreSQL:
+		return lexerConfig{doubleQuoteIdent: true, dollarQuote: true}
+	case SQLite:
+		return lexerConfig{doubleQuoteIdent: true, bracketIdent: true}
+	case SQLServer:
+		return lexerConfig{doubleQuoteIdent: true, bracketIdent: true, batchSeparator: true, hashIdent: true}
+	}
+	return lexerConfig{backslashEscape: true, conditionalCode: true, hashComment: true}
+}
+
+// foldsUnquotedIdent reports whether unquoted identifiers are always folded to lower case by the database
+func (d Dialect) foldsUnquotedIdent() bool {
+	return d == PostgreSQL
+}
+
+func (d Dialect) typeMapper() TypeMapper {
+	switch d {
+	case PostgreSQL:
+		return postgresMapping
+	case SQLite:
+		return sqliteAffinityMapper{}
+	case SQLServer:
+		return sqlServerMapping
+	}
+	return mapping
+}
+
+// quotedTypes reports whether type names may be quoted identifiers, e.g. [int] in scripts of SQL Server
+func (d Dialect) quotedTypes() bool {
+	return d == SQLServer
+}
+
+// multiWordTypes reports whether a type name is any sequence of identifiers, e.g. UNSIGNED BIG INT
+func (d Dialect) multiWordTypes() bool {
+	return d == SQLite
+}
+
+// isAutoIncrementType reports whether the type itself implies an auto increment column, e.g. SERIAL
+func (d Dialect) isAutoIncrementType(name string) bool {
+	switch d {
+	case PostgreSQL:
+		switch strings.ToUpper(name) {
+		case "SMALLSERIAL", "SERIAL", "BIGSERIAL", "SERIAL2", "SERIAL4", "SERIAL8":
+			return true
+		}
+	}
+	return false
+}
+
+// isSequenceDefault reports whether the default expression takes the next value of a sequence,
+// e.g. nextval('users_id_seq'::regclass) which pg_dump writes for SERIAL columns
+func isSequenceDefault(expr string) bool {
+	return strings.HasPrefix(strings.ToLower(expr), "nextval(")
+}
+
+// sqliteAffinityMapper maps the declared type by the rules of sqlite column affinity,
+// see https://www.sqlite.org/datatype3.html#determination_of_column_affinity
+type sqliteAffinityMapper struct{}
+
+func (sqliteAffinityMapper) getGoStructType(sqlField string) MappedGoFieldType {
+	upper := strings.ToUpper(sqlField)
+	switch {
+	case upper == "" || upper == "ANY":
+		return FeildTypeDefault
+	case strings.Contains(upper, "INT"):
+		return FeildTypeInt64
+	case strings.Contains(upper, "CHAR"), strings.Contains(upper, "CLOB"), strings.Contains(upper, "TEXT"):
+		return FeildTypeString
+	case strings.Contains(upper, "BLOB"):
+		return FieldTypeBytes
+	case strings.Contains(upper, "REAL"), strings.Contains(upper, "FLOA"), strings.Contains(upper, "DOUB"):
+		return FeildTypeFloat64
+	// the numeric affinity, drivers scan the common declared types of it into bool and time.Time
+	case strings.Contains(upper, "BOOL"):
+		return FieldTypeBool
+	case strings.Contains(upper, "DATE"), strings.Contains(upper, "TIME"):
+		return FieldTypeTime
+	}
+	return FeildTypeFloat64
+}
+
+// sqliteRowIDAlias marks the INTEGER PRIMARY KEY column of a rowid table as auto increment,
+// since such a column is an alias of the rowid
+func sqliteRowIDAlias(stmt *CreateTableStmt) {
+	for _, option := range stmt.Options {
+		if option.Name == "WITHOUT ROWID" {
+			return
+		}
+	}
+	var pk []string
+	for _, column := range stmt.Columns {
+		if column.PrimaryKey {
+			pk = append(pk, column.Name)
+		}
+	}
+	for _, constraint := range stmt.Constraints {
+		if constraint.Kind == IndexPrimary {
+			pk = append(pk, constraint.Columns...)
+		}
+	}
+	if len(pk) != 1 {
+		return
+	}
+	for _, column := range stmt.Columns {
+		if column.Name == pk[0] && column.Type != nil && strings.EqualFold(column.Type.Name, "INTEGER") {
+			column.AutoIncrement = true
+			column.NotNull = true
+		}
+	}
+}
+
+var (
+	postgresMapping  FieldTypeMapper
+	sqlServerMapping FieldTypeMapper
+)
+
+func init() {
+	postgresMapping = make(FieldTypeMapper)
+	postgresMapping[FeildTypeString] = []string{
+		"CHAR", "CHARACTER", "VARCHAR", "CHARACTER VARYING", "BPCHAR", "TEXT", "CITEXT", "NAME", "UUID",
+		"INET", "CIDR", "MACADDR", "XML", "INTERVAL", "MONEY", "TSVECTOR",
+	}
+	postgresMapping[FeildTypeInt64] = []string{
+		"BIGINT", "INT8", "BIGSERIAL", "SERIAL8",
+	}
+	postgresMapping[FeildTypeInt32] = []string{
+		"INTEGER", "INT", "INT4", "SERIAL", "SERIAL4",
+	}
+	postgresMapping[FeildTypeInt] = []string{
+		"SMALLINT", "INT2", "SMALLSERIAL", "SERIAL2",
+	}
+	postgresMapping[FeildTypeFloat64] = []string{
+		"DOUBLE PRECISION", "FLOAT8", "FLOAT", "NUMERIC", "DECIMAL",
+	}
+	postgresMapping[FeildTypeFloat32] = []string{
+		"REAL", "FLOAT4",
+	}
+	postgresMapping[FieldTypeBool] = []string{
+		"BOOLEAN", "BOOL",
+	}
+	postgresMapping[FieldTypeTime] = []string{
+		"DATE", "TIME", "TIMETZ", "TIMESTAMP", "TIMESTAMPTZ", "TIME WITH TIME ZONE", "TIME WITHOUT TIME ZONE",
+		"TIMESTAMP WITH TIME ZONE", "TIMESTAMP WITHOUT TIME ZONE",
+	}
+	postgresMapping[FieldTypeBytes] = []string{
+		"BYTEA",
+	}
+	postgresMapping[FieldTypeJSON] = []string{
+		"JSON", "JSONB",
+	}
+
+	sqlServerMapping = make(FieldTypeMapper)
+	sqlServerMapping[FeildTypeString] = []string{
+		"CHAR", "VARCHAR", "NCHAR", "NVARCHAR", "TEXT", "NTEXT", "UNIQUEIDENTIFIER", "XML", "SYSNAME",
+	}
+	sqlServerMapping[FeildTypeInt64] = []string{
+		"BIGINT",
+	}
+	sqlServerMapping[FeildTypeInt32] = []string{
+		"INT",
+	}
+	sqlServerMapping[FeildTypeInt] = []string{
+		"SMALLINT", "TINYINT",
+	}
+	sqlServerMapping[FeildTypeFloat64] = []string{
+		"FLOAT", "DECIMAL", "NUMERIC", "MONEY", "SMALLMONEY",
+	}
+	sqlServerMapping[FeildTypeFloat32] = []string{
+		"REAL",
+	}
+	sqlServerMapping[FieldTypeBool] = []string{
+		"BIT",
+	}
+	sqlServerMapping[FieldTypeTime] = []string{
+		"DATE", "TIME", "DATETIME", "DATETIME2", "SMALLDATETIME", "DATETIMEOFFSET",
+	}
+	// TIMESTAMP is a synonym of ROWVERSION in SQL Server
+	sqlServerMapping[FieldTypeBytes] = []string{
+		"BINARY", "VARBINARY", "IMAGE", "ROWVERSION", "TIMESTAMP",
+	}
+}
@@ -0,0 +1,226 @@
+package sqlconverter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgreSQLDialect(t *testing.T) {
+	sqls := []string{
+		`CREATE TABLE public."UserAccount" (
+			id bigserial PRIMARY KEY,
+			"Email" character varying(255) NOT NULL UNIQUE,
+			uid uuid DEFAULT gen_random_uuid() NOT NULL,
+			name text DEFAULT 'anonymous'::character varying,
+			tags text[] DEFAULT ARRAY[]::text[],
+			profile jsonb,
+			balance numeric(12,2) NOT NULL DEFAULT 0,
+			ratio double precision,
+			active boolean NOT NULL DEFAULT true,
+			avatar bytea,
+			seq integer GENERATED BY DEFAULT AS IDENTITY,
+			created_at timestamp(3) with time zone NOT NULL DEFAULT now()
+		)`,
+		`COMMENT ON TABLE public."UserAccount" IS 'the user''s account'`,
+		`COMMENT ON COLUMN public."UserAccount".name IS 'Display Name'`,
+		`COMMENT ON COLUMN missing.name IS 'ignored'`,
+	}
+	parser := &CreateTableSQLParser{
+		Sqls:         sqls,
+		Dialect:      PostgreSQL,
+		NullStrategy: NullPointer,
+	}
+	if err := parser.SetDefault().parseSQL(); err != nil {
+		t.Fatal(err)
+	}
+
+	table := parser.schema.GetTable("UserAccount")
+	if !assert.NotNil(t, table) {
+		return
+	}
+	assert.Equal(t, "the user's account", table.Comment)
+	assert.Equal(t, "Display Name", table.GetField("name").FieldComment)
+	assert.Equal(t, "'anonymous'::character varying", *table.GetField("name").Default)
+	assert.Equal(t, "timestamp with time zone", table.GetField("created_at").FieldType)
+	assert.Equal(t, 3, table.GetField("created_at").Precision)
+	assert.True(t, table.GetField("id").AutoIncrement)
+	assert.True(t, table.GetField("seq").AutoIncrement)
+	assert.Equal(t, []*IndexInfo{{Kind: IndexUnique, Columns: []string{"Email"}}}, table.Indexes)
+
+	expecteds := map[string]MappedGoFieldType{
+		"id":         "int64",
+		"Email":      "string",
+		"uid":        "string",
+		"name":       "*string",
+		"tags":       "[]string",
+		"profile":    "json.RawMessage",
+		"balance":    "float64",
+		"ratio":      "*float64",
+		"active":     "bool",
+		"avatar":     "[]byte",
+		"seq":        "int32",
+		"created_at": "time.Time",
+	}
+	for name, expected := range expecteds {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, expected, parser.getGoType(table.GetField(name)))
+		})
+	}
+}
+
+func TestPostgreSQLLexer(t *testing.T) {
+	inputs := []string{
+		`"Quoted ""Name"""`,
+		`'a\b'`,
+		`$$ it's a body $$`,
+		`$fn$ SELECT 'x' $fn$`,
+	}
+	expecteds := []Token{
+		{Kind: TokenQuotedIdent, Value: `Quoted "Name"`},
+		{Kind: TokenString, Value: `a\b`},
+		{Kind: TokenString, Value: ` it's a body `},
+		{Kind: TokenString, Value: ` SELECT 'x' `},
+	}
+
+	for i, input := range inputs {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			tokens, err := tokenize(input, PostgreSQL.lexerConfig())
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, expecteds[i], Token{Kind: tokens[0].Kind, Value: tokens[0].Value})
+		})
+	}
+}
+
+func TestSQLiteDialect(t *testing.T) {
+	sqls := []string{
+		`CREATE TABLE IF NOT EXISTS "notes" (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			[title] VARCHAR(64) NOT NULL COLLATE NOCASE,
+			body TEXT,
+			size UNSIGNED BIG INT,
+			score DOUBLE PRECISION,
+			price NUMERIC(10, 2),
+			done BOOLEAN NOT NULL DEFAULT 0,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			data BLOB,
+			extra,
+			raw ANY
+		) STRICT`,
+		`CREATE TABLE tags (tag_id INTEGER, name TEXT, PRIMARY KEY (tag_id))`,
+		`CREATE TABLE kv (k INTEGER PRIMARY KEY, v TEXT) WITHOUT ROWID`,
+		`CREATE TABLE pairs (a INTEGER, b INTEGER, PRIMARY KEY (a, b))`,
+	}
+	parser := &CreateTableSQLParser{
+		Sqls:         sqls,
+		Dialect:      SQLite,
+		NullStrategy: NullSQL,
+	}
+	if err := parser.SetDefault().parseSQL(); err != nil {
+		t.Fatal(err)
+	}
+
+	notes := parser.schema.GetTable("notes")
+	if !assert.NotNil(t, notes) {
+		return
+	}
+	expecteds := map[string]MappedGoFieldType{
+		"id":         "int64",
+		"title":      "string",
+		"body":       "sql.NullString",
+		"size":       "sql.NullInt64",
+		"score":      "sql.NullFloat64",
+		"price":      "sql.NullFloat64",
+		"done":       "bool",
+		"created_at": "sql.NullTime",
+		"data":       "[]byte",
+		"extra":      "interface{}",
+		"raw":        "interface{}",
+	}
+	assert.Len(t, notes.Fields, len(expecteds))
+	for name, expected := range expecteds {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, expected, parser.getGoType(notes.GetField(name)))
+		})
+	}
+	assert.Equal(t, "UNSIGNED BIG INT", notes.GetField("size").FieldType)
+
+	// only the single INTEGER PRIMARY KEY of rowid tables aliases the rowid
+	rowIDs := map[string]bool{
+		"notes.id":    true,
+		"tags.tag_id": true,
+	}
+	for _, table := range parser.schema.Tables {
+		for _, field := range table.Fields {
+			name := table.TableName + "." + field.FieldName
+			assert.Equal(t, rowIDs[name], field.AutoIncrement, name)
+		}
+	}
+}
+
+func TestSQLServerDialect(t *testing.T) {
+	script := `SET ANSI_NULLS ON
+GO
+SET QUOTED_IDENTIFIER ON
+GO
+CREATE TABLE [dbo].[Orders](
+	[OrderID] [int] IDENTITY(1,1) NOT NULL,
+	[CustomerID] UNIQUEIDENTIFIER NOT NULL,
+	[Note] NVARCHAR(MAX) NULL CONSTRAINT [DF_Orders_Note] DEFAULT (N'none'),
+	[Paid] BIT NOT NULL,
+	[Amount] MONEY NULL,
+	[PlacedAt] DATETIME2(7) NOT NULL,
+	[Version] ROWVERSION,
+ CONSTRAINT [PK_Orders] PRIMARY KEY CLUSTERED ([OrderID] ASC)
+ WITH (PAD_INDEX = OFF, STATISTICS_NORECOMPUTE = OFF) ON [PRIMARY]
+) ON [PRIMARY] TEXTIMAGE_ON [PRIMARY]
+GO
+`
+	sqls, err := readCreateSQL([]byte(script), SQLServer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser := &CreateTableSQLParser{
+		Sqls:         sqls,
+		Dialect:      SQLServer,
+		NullStrategy: NullSQL,
+	}
+	if err = parser.SetDefault().parseSQL(); err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(t, parser.schema.Tables, 1) {
+		return
+	}
+
+	orders := parser.schema.Tables[0]
+	assert.Equal(t, "Orders", orders.TableName)
+	assert.Equal(t, &IndexInfo{Name: "PK_Orders", Kind: IndexPrimary, Columns: []string{"OrderID"}}, orders.PrimaryKey)
+	assert.True(t, orders.GetField("OrderID").AutoIncrement)
+	assert.Equal(t, "(N'none')", *orders.GetField("Note").Default)
+	assert.Equal(t, 7, orders.GetField("PlacedAt").Precision)
+
+	expecteds := map[string]MappedGoFieldType{
+		"OrderID":    "int32",
+		"CustomerID": "string",
+		"Note":       "sql.NullString",
+		"Paid":       "bool",
+		"Amount":     "sql.NullFloat64",
+		"PlacedAt":   "time.Time",
+		"Version":    "[]byte",
+	}
+	assert.Len(t, orders.Fields, len(expecteds))
+	for name, expected := range expecteds {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, expected, parser.getGoType(orders.GetField(name)))
+		})
+	}
+
+	temp, err := extractTableStruct("CREATE TABLE #orders (id int NOT NULL)", Options{Dialect: SQLServer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "#orders", temp.TableName)
+}
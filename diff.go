@@ -0,0 +1,39 @@
+package sqlconverter
+
+import (
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// diffContext is the number of unchanged lines around the changes of a hunk
+const diffContext = 3
+
+// unifiedDiff returns the unified diff of a and b, empty when they are the same
+func unifiedDiff(aName, bName string, a, b []byte) string {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(string(a)),
+		B:        splitLines(string(b)),
+		FromFile: aName,
+		ToFile:   bName,
+		Context:  diffContext,
+	})
+	if err != nil {
+		// the diff is written into a strings.Builder, which never fails
+		panic(err)
+	}
+	return diff
+}
+
+// splitLines splits s after the line breaks, a line break is added to the last line when s does not end with one
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		return lines[:len(lines)-1]
+	}
+	lines[len(lines)-1] += "\n"
+	return lines
+}
@@ -0,0 +1,110 @@
+package sqlconverter
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	inputs := [][2]string{
+		{"a\nb\nc\n", "a\nb\nc\n"},
+		{"", "a\nb\n"},
+		{"a\nb\n", ""},
+		{"1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n", "1\n2\nx\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n14\n15\n"},
+		{"1\n2\n3\n4\n5\n6\n7\n8\n", "1\nx\n3\n4\n5\n6\n7\ny\n"},
+		{"a\nb", "a\nc\n"},
+	}
+	expecteds := []string{
+		"",
+		"--- a\n+++ b\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		"--- a\n+++ b\n@@ -1,2 +0,0 @@\n-a\n-b\n",
+		"--- a\n+++ b\n@@ -1,5 +1,6 @@\n 1\n 2\n+x\n 3\n 4\n 5\n@@ -10,6 +11,5 @@\n 10\n 11\n 12\n-13\n 14\n 15\n",
+		"--- a\n+++ b\n@@ -1,8 +1,8 @@\n 1\n-2\n+x\n 3\n 4\n 5\n 6\n 7\n-8\n+y\n",
+		"--- a\n+++ b\n@@ -1,2 +1,2 @@\n a\n-b\n+c\n",
+	}
+	for i, input := range inputs {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			assert.Equal(t, expecteds[i], unifiedDiff("a", "b", []byte(input[0]), []byte(input[1])))
+		})
+	}
+
+}
+
+func TestParseDryRun(t *testing.T) {
+	var out, info bytes.Buffer
+	stdout, stderr = &out, &info
+	defer func() {
+		stdout, stderr = os.Stdout, os.Stderr
+	}()
+
+	dir := t.TempDir()
+	existing := "package models\n\n// table: users\ntype Users struct {\n\tID int32 `json:\"id\" db:\"id\"`\n}\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "users.go"), []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "logs.go"), []byte("package models\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	parse := func(dryRun, diff bool, mode WriteMode) error {
+		out.Reset()
+		parser := &CreateTableSQLParser{
+			Sqls: []string{
+				"CREATE TABLE users (id int NOT NULL)",
+				"CREATE TABLE orders (id int NOT NULL, user_id int NOT NULL)",
+				"CREATE TABLE logs (id int NOT NULL)",
+			},
+			TargetDir: dir,
+			PerTable:  true,
+			Mode:      mode,
+			DryRun:    dryRun,
+			Diff:      diff,
+		}
+		return parser.Parse()
+	}
+
+	if err := parse(true, false, OVERWRITE); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmt.Sprintf("%s/users.go: unchanged\n%s/orders.go: new file\n%s/logs.go: modified\n", dir, dir, dir), out.String())
+
+	if err := parse(false, true, OVERWRITE); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmt.Sprintf("--- /dev/null\n+++ %s/orders.go\n@@ -0,0 +1,7 @@\n", dir)+
+		"+package models\n+\n+// table: orders\n+type Orders struct {\n+\tID     int32 `json:\"id\" db:\"id\"`\n+\tUserID int32 `json:\"user_id\" db:\"user_id\"`\n+}\n"+
+		fmt.Sprintf("--- %s/logs.go\n+++ %s/logs.go\n@@ -1 +1,6 @@\n", dir, dir)+
+		" package models\n+\n+// table: logs\n+type Logs struct {\n+\tID int32 `json:\"id\" db:\"id\"`\n+}\n", out.String())
+
+	// the existing go files are merged by default, and they can not be appended to
+	if err := parse(true, false, NONE); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmt.Sprintf("%s/users.go: unchanged\n%s/orders.go: new file\n%s/logs.go: modified\n", dir, dir, dir), out.String())
+	assert.Error(t, parse(false, true, APPEND))
+
+	// nothing is written
+	b, err := ioutil.ReadFile(filepath.Join(dir, "users.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, existing, string(b))
+	_, err = os.Stat(filepath.Join(dir, "orders.go"))
+	assert.True(t, os.IsNotExist(err))
+	assert.NotContains(t, info.String(), "Output:")
+
+	// the standard output is written as it is
+	for _, parser := range []*CreateTableSQLParser{
+		{Sqls: []string{"CREATE TABLE users (id int NOT NULL)"}, OutputFile: StdoutPath, DryRun: true},
+		{Sqls: []string{"CREATE TABLE users (id int NOT NULL)"}, OutputFile: StdoutPath, Diff: true},
+	} {
+		out.Reset()
+		assert.Error(t, parser.Parse())
+		assert.Empty(t, out.String())
+	}
+}
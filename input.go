@@ -0,0 +1,283 @@
+package sqlconverter
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// StdinPath is the path which reads the standard input
+const StdinPath = "-"
+
+var stdin io.Reader = os.Stdin
+
+// sqlSource is the statements read from one sql file, or the tables read from one database file
+type sqlSource struct {
+	Path   string
+	Sqls   []string
+	Tables []*TableStruct
+}
+
+// readSources reads the statements of the given sql files, directories and glob patterns in order,
+// a file reached by more than one path is read once
+func readSources(paths []string, opts Options) ([]*sqlSource, error) {
+	var (
+		res  []*sqlSource
+		seen = make(map[string]struct{})
+	)
+	for _, path := range paths {
+		if path == StdinPath {
+			if _, exist := seen[StdinPath]; exist {
+				continue
+			}
+			seen[StdinPath] = struct{}{}
+			source, err := readStdin(opts.Dialect)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, source)
+			continue
+		}
+		matches := []string{path}
+		if IsGlob(path) {
+			var err error
+			if matches, err = filepath.Glob(path); err != nil {
+				return nil, fmt.Errorf("invalid pattern %s, err: %v", path, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no sql file matches %s", path)
+			}
+		}
+		for _, match := range matches {
+			sources, err := readPath(match, opts)
+			if err != nil {
+				return nil, err
+			}
+			for _, source := range sources {
+				if _, exist := seen[filepath.Clean(source.Path)]; exist {
+					continue
+				}
+				seen[filepath.Clean(source.Path)] = struct{}{}
+				res = append(res, source)
+			}
+		}
+	}
+	return res, nil
+}
+
+// IsGlob reports whether the path is a glob pattern
+func IsGlob(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// readPath reads a sql file, a sqlite database file or a schema IR file, or the sql files under a directory recursively
+// in the order of their paths. The directories holding migrations are read as migrations, see readMigrations
+func readPath(path string, opts Options) ([]*sqlSource, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		read := readSQLFile
+		switch {
+		case isSQLiteFile(path):
+			read = readSQLiteFile
+		case isIRFile(path):
+			read = readIRFile
+		}
+		source, err := read(path, opts)
+		if err != nil {
+			return nil, err
+		}
+		return []*sqlSource{source}, nil
+	}
+
+	var res []*sqlSource
+	// the migrations of a migration directory are read in the order of their versions before its other files
+	migrationDirs := make(map[string]bool)
+	err = filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			migrations, err := listMigrations(p)
+			if err != nil || len(migrations) == 0 {
+				return err
+			}
+			sources, err := readMigrations(migrations, opts.Dialect)
+			if err != nil {
+				return err
+			}
+			res = append(res, sources...)
+			migrationDirs[filepath.Clean(p)] = true
+			return nil
+		}
+		if !strings.EqualFold(filepath.Ext(p), ".sql") {
+			return nil
+		}
+		if migrationDirs[filepath.Dir(p)] && migrationFilePattern.MatchString(info.Name()) {
+			return nil
+		}
+		source, err := readSQLFile(p, opts)
+		if err != nil {
+			return err
+		}
+		res = append(res, source)
+		return nil
+	})
+	return res, err
+}
+
+// sourceDialect returns the dialect implied by the file, i.e. SQLITE for a sqlite database file
+// and the dialect of a schema IR file, empty for the other files
+func sourceDialect(path string) Dialect {
+	switch {
+	case isSQLiteFile(path):
+		return SQLite
+	case isIRFile(path):
+		f, err := os.Open(path)
+		if err != nil {
+			return ""
+		}
+		defer f.Close()
+		if ir, err := ReadIR(f); err == nil {
+			return ir.Dialect
+		}
+	}
+	return ""
+}
+
+func readSQLFile(path string, opts Options) (*sqlSource, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sqls, err := readCreateSQL(content, opts.Dialect)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &sqlSource{Path: path, Sqls: sqls}, nil
+}
+
+// readStdin reads the statements of the standard input, which is given as the path "-"
+func readStdin(dialect Dialect) (*sqlSource, error) {
+	content, err := ioutil.ReadAll(stdin)
+	if err != nil {
+		return nil, err
+	}
+	sqls, err := readCreateSQL(content, dialect)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", StdinPath, err)
+	}
+	return &sqlSource{Path: StdinPath, Sqls: sqls}, nil
+}
+
+var (
+	// showCreateBatchRow is a row of SHOW CREATE TABLE printed by mysql -e in batch mode, e.g. users\tCREATE TABLE ...
+	showCreateBatchRow = regexp.MustCompile(`(?i)^[^\t]+\t(CREATE\s.*)$`)
+	// showCreateVerticalRow starts a row printed by mysql -e in vertical mode, i.e. the \G terminator
+	showCreateVerticalRow = regexp.MustCompile(`^\*+ \d+\. row \*+$`)
+	// showCreateVerticalField is the statement field of a vertical row, e.g. Create Table: CREATE TABLE ...
+	showCreateVerticalField = regexp.MustCompile(`(?i)^\s*Create (Table|View): (.*)$`)
+)
+
+// unwrapShowCreate extracts the statements from the output of mysql -e 'SHOW CREATE TABLE x'
+// in batch or vertical mode, other content is returned as it is
+func unwrapShowCreate(content string) string {
+	lines := strings.Split(strings.TrimSpace(content), "\n")
+	header := strings.EqualFold(strings.TrimSpace(lines[0]), "Table\tCreate Table") ||
+		strings.HasPrefix(strings.ToLower(lines[0]), "view\tcreate view\t")
+	switch {
+	case header || showCreateBatchRow.MatchString(lines[0]):
+		if header {
+			lines = lines[1:]
+		}
+		var res []string
+		for _, line := range lines {
+			if matches := showCreateBatchRow.FindStringSubmatch(line); matches != nil {
+				// the statement of a view is followed by its character set and collation
+				stmt := strings.Split(matches[1], "\t")[0]
+				res = append(res, unescapeBatch(stmt)+";")
+			}
+		}
+		return strings.Join(res, "\n")
+	case showCreateVerticalRow.MatchString(strings.TrimSpace(lines[0])):
+		var (
+			res []string
+			in  bool
+		)
+		for _, line := range lines {
+			switch matches := showCreateVerticalField.FindStringSubmatch(line); {
+			case showCreateVerticalRow.MatchString(strings.TrimSpace(line)):
+				in = false
+			case matches != nil:
+				in = true
+				res = append(res, ";", matches[2])
+			case in && !strings.HasPrefix(strings.TrimSpace(line), "character_set_client:") &&
+				!strings.HasPrefix(strings.TrimSpace(line), "collation_connection:"):
+				res = append(res, line)
+			}
+		}
+		return strings.Join(res, "\n")
+	}
+	return content
+}
+
+// unescapeBatch reverts the escaping of the batch mode of mysql, which writes newline, tab,
+// NUL and backslash as \n, \t, \0 and \\
+func unescapeBatch(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case '0':
+			b.WriteByte(0)
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// ddlVerbs start the statements which define or change tables
+var ddlVerbs = []string{"create", "alter", "rename", "drop", "comment"}
+
+// readCreateSQL splits the content into statements and keeps the ones which define or change tables
+func readCreateSQL(content []byte, dialect Dialect) ([]string, error) {
+
+	eles, err := splitStatements(unwrapShowCreate(string(content)), dialect.lexerConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	var res []string
+	for _, ele := range eles {
+		if isDDL(ele) {
+			res = append(res, ele)
+		}
+	}
+	return res, nil
+}
+
+// isDDL reports whether the statement defines or changes tables
+func isDDL(stmt string) bool {
+	lower := strings.ToLower(stmt)
+	for _, verb := range ddlVerbs {
+		if strings.HasPrefix(lower, verb) {
+			return true
+		}
+	}
+	return false
+}
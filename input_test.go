@@ -0,0 +1,152 @@
+package sqlconverter
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadSources(t *testing.T) {
+	cases := map[string][]string{
+		"Directory": {"./testdata/tables"},
+		"Glob":      {"./testdata/tables/shop/*.sql", "./testdata/tables/users.sql"},
+		"Duplicate": {"./testdata/tables/users.sql", "./testdata/tables"},
+		"Nested":    {"./testdata"},
+	}
+	expected := map[string][]string{
+		"Directory": {"testdata/tables/shop/items.sql", "testdata/tables/shop/orders.sql", "testdata/tables/users.sql"},
+		"Glob":      {"testdata/tables/shop/items.sql", "testdata/tables/shop/orders.sql", "testdata/tables/users.sql"},
+		"Duplicate": {"testdata/tables/users.sql", "testdata/tables/shop/items.sql", "testdata/tables/shop/orders.sql"},
+		"Nested": {
+			"testdata/goose/20230101120000_create_users.sql",
+			"testdata/goose/20230102120000_add_touch.sql",
+			"testdata/goose/20230103120000_rename_users.sql",
+			"testdata/migrate/1_create_users.up.sql",
+			"testdata/migrate/2_add_name.up.sql",
+			"testdata/migrate/10_create_orders.up.sql",
+			"testdata/mysqldump.sql",
+			"testdata/tables/shop/items.sql",
+			"testdata/tables/shop/orders.sql",
+			"testdata/tables/users.sql",
+		},
+	}
+
+	for name, paths := range cases {
+		t.Run(name, func(t *testing.T) {
+			sources, err := readSources(paths, Options{Dialect: MySQL})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var actual []string
+			for _, source := range sources {
+				actual = append(actual, filepath.ToSlash(filepath.Clean(source.Path)))
+			}
+			assert.Equal(t, expected[name], actual)
+		})
+	}
+}
+
+func TestReadMigrationDirectory(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"1_create_users.up.sql":   "CREATE TABLE users (id int);",
+		"1_create_users.down.sql": "DROP TABLE users;",
+		"logs.sql":                "CREATE TABLE logs (id int);",
+		"readme.md":               "migrations",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sources, err := readSources([]string{dir}, Options{Dialect: MySQL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actual []string
+	for _, source := range sources {
+		actual = append(actual, filepath.Base(source.Path))
+	}
+	assert.Equal(t, []string{"1_create_users.up.sql", "logs.sql"}, actual)
+}
+
+func TestReadSourcesError(t *testing.T) {
+	inputs := [][]string{
+		{"./testdata/unknown.sql"},
+		{"./testdata/*.unknown"},
+	}
+	for _, paths := range inputs {
+		_, err := readSources(paths, Options{Dialect: MySQL})
+		assert.Error(t, err)
+	}
+}
+
+func TestLoadSourceFile(t *testing.T) {
+	parser := &CreateTableSQLParser{
+		SqlFile:  "./testdata/tables/users.sql",
+		SqlFiles: []string{"./testdata/tables/shop"},
+		Sqls:     []string{"CREATE TABLE logs (id int)"},
+	}
+	parser.SetDefault()
+	if err := parser.load(); err != nil {
+		t.Fatal(err)
+	}
+	if err := parser.parseSQL(); err != nil {
+		t.Fatal(err)
+	}
+
+	actual := make(map[string]string)
+	for _, table := range parser.schema.Tables {
+		actual[table.TableName] = filepath.ToSlash(table.SourceFile)
+	}
+	assert.Equal(t, map[string]string{
+		"logs":   "",
+		"users":  "./testdata/tables/users.sql",
+		"items":  "testdata/tables/shop/items.sql",
+		"orders": "testdata/tables/shop/orders.sql",
+	}, actual)
+	assert.Len(t, parser.relations, 2)
+}
+
+func TestUnwrapShowCreate(t *testing.T) {
+	inputs := []string{
+		"Table\tCreate Table\nusers\tCREATE TABLE `users` (\\n  `id` int NOT NULL,\\n  `name` varchar(8) COMMENT 'a\\\\b'\\n) ENGINE=InnoDB\n",
+		"users\tCREATE TABLE `users` (\\n  `id` int\\n)\norders\tCREATE TABLE `orders` (\\n  `id` int\\n)\n",
+		"*************************** 1. row ***************************\n" +
+			"       Table: users\n" +
+			"Create Table: CREATE TABLE `users` (\n  `id` int\n) ENGINE=InnoDB\n",
+		"CREATE TABLE `users` (\n\t`id` int\n);\n",
+	}
+	expecteds := []string{
+		"CREATE TABLE `users` (\n  `id` int NOT NULL,\n  `name` varchar(8) COMMENT 'a\\b'\n) ENGINE=InnoDB;",
+		"CREATE TABLE `users` (\n  `id` int\n);\nCREATE TABLE `orders` (\n  `id` int\n);",
+		";\nCREATE TABLE `users` (\n  `id` int\n) ENGINE=InnoDB",
+		"CREATE TABLE `users` (\n\t`id` int\n);\n",
+	}
+	for i, input := range inputs {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			assert.Equal(t, expecteds[i], unwrapShowCreate(input))
+		})
+	}
+}
+
+func TestReadStdin(t *testing.T) {
+	stdin = strings.NewReader("users\tCREATE TABLE `users` (\\n  `id` int NOT NULL\\n) ENGINE=InnoDB\n")
+	defer func() {
+		stdin = os.Stdin
+	}()
+
+	sources, err := readSources([]string{StdinPath, StdinPath}, Options{Dialect: MySQL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []*sqlSource{
+		{Path: StdinPath, Sqls: []string{"CREATE TABLE `users` (\n  `id` int NOT NULL\n) ENGINE=InnoDB"}},
+	}, sources)
+}
@@ -0,0 +1,390 @@
+package sqlconverter
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Introspector reads the tables of a running database, the tables are the same as the ones
+// parsed from their CREATE TABLE statements as far as the database reports them
+type Introspector interface {
+	Introspect(db *sql.DB, opts Options) ([]*TableStruct, error)
+}
+
+var (
+	introspectorsMu sync.RWMutex
+	introspectors   = make(map[Dialect]Introspector)
+)
+
+// RegisterIntrospector makes the introspector read the databases of the dialect, e.g. -dsn of SQLSERVER,
+// it replaces the built-in introspector of the dialect and the ones registered before, nil restores the built-in one
+func RegisterIntrospector(dialect Dialect, introspector Introspector) {
+	introspectorsMu.Lock()
+	defer introspectorsMu.Unlock()
+	if introspector == nil {
+		delete(introspectors, dialect)
+		return
+	}
+	introspectors[dialect] = introspector
+}
+
+// introspector returns the introspector registered for the dialect, or the built-in one of its databases
+func (d Dialect) introspector() (Introspector, error) {
+	introspectorsMu.RLock()
+	introspector, exist := introspectors[d]
+	introspectorsMu.RUnlock()
+	if exist {
+		return introspector, nil
+	}
+	switch d {
+	case MySQL, PostgreSQL:
+		return &InformationSchemaIntrospector{Dialect: d}, nil
+	case SQLite:
+		return sqliteIntrospector{}, nil
+	}
+	return nil, fmt.Errorf("introspection of %s is not supported", d)
+}
+
+// defaultDriver returns the name of the database/sql driver usually registered for the dialect
+func (d Dialect) defaultDriver() string {
+	switch d {
+	case PostgreSQL:
+		return "postgres"
+	case SQLite:
+		return "sqlite"
+	case SQLServer:
+		return "sqlserver"
+	}
+	return "mysql"
+}
+
+// introspectDSN opens the database by the driver and reads its tables by the introspector of the dialect
+func introspectDSN(driver, dsn string, opts Options) ([]*TableStruct, error) {
+	introspector, err := opts.Dialect.introspector()
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open database failed, err: %v", err)
+	}
+	defer db.Close()
+	return introspector.Introspect(db, opts)
+}
+
+type sqliteIntrospector struct{}
+
+func (sqliteIntrospector) Introspect(db *sql.DB, opts Options) ([]*TableStruct, error) {
+	return introspectSQLite(db, opts)
+}
+
+// InformationSchemaIntrospector reads the tables of a MySQL or PostgreSQL database from
+// information_schema.COLUMNS, STATISTICS, TABLE_CONSTRAINTS and KEY_COLUMN_USAGE. PostgreSQL reports
+// neither comments nor the indexes which are not constraints in information_schema, so they are not read
+type InformationSchemaIntrospector struct {
+	Dialect Dialect
+	Schema  string // the schema or database whose tables are read, default: the current one of the connection
+}
+
+func (in *InformationSchemaIntrospector) Introspect(db *sql.DB, opts Options) ([]*TableStruct, error) {
+	schema := in.Schema
+	if schema == "" {
+		query := "SELECT DATABASE()"
+		if in.Dialect == PostgreSQL {
+			query = "SELECT current_schema()"
+		}
+		var current sql.NullString
+		if err := db.QueryRow(query).Scan(&current); err != nil {
+			return nil, fmt.Errorf("read current schema failed, err: %v", err)
+		}
+		if !current.Valid {
+			return nil, fmt.Errorf("no schema is selected by the connection")
+		}
+		schema = current.String
+	}
+
+	tables, err := in.tables(db, schema, opts)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*CreateTableStmt)
+	for _, stmt := range tables {
+		byName[stmt.Table] = stmt
+	}
+	steps := []func(*sql.DB, string, map[string]*CreateTableStmt, Options) error{
+		in.columns, in.keys, in.foreignKeys,
+	}
+	for _, step := range steps {
+		if err := step(db, schema, byName, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	var res []*TableStruct
+	for _, stmt := range tables {
+		res = append(res, stmt.toTableStruct())
+	}
+	return res, nil
+}
+
+func (in *InformationSchemaIntrospector) tables(db *sql.DB, schema string, opts Options) ([]*CreateTableStmt, error) {
+	query := "SELECT TABLE_NAME, TABLE_COMMENT FROM information_schema.TABLES " +
+		"WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'BASE TABLE' ORDER BY TABLE_NAME"
+	if in.Dialect == PostgreSQL {
+		query = "SELECT TABLE_NAME, '' FROM information_schema.TABLES " +
+			"WHERE TABLE_SCHEMA = $1 AND TABLE_TYPE = 'BASE TABLE' ORDER BY TABLE_NAME"
+	}
+	rows, err := db.Query(query, schema)
+	if err != nil {
+		return nil, fmt.Errorf("read information_schema.TABLES failed, err: %v", err)
+	}
+	defer rows.Close()
+
+	var res []*CreateTableStmt
+	for rows.Next() {
+		var name, comment string
+		if err := rows.Scan(&name, &comment); err != nil {
+			return nil, err
+		}
+		stmt := &CreateTableStmt{Schema: schema, Table: introspectedIdent(name, opts)}
+		if comment != "" {
+			stmt.Options = append(stmt.Options, &TableOption{Name: "COMMENT", Value: comment})
+		}
+		res = append(res, stmt)
+	}
+	return res, rows.Err()
+}
+
+// mysqlOnUpdate matches the ON UPDATE expression in the EXTRA of a column of MySQL
+var mysqlOnUpdate = regexp.MustCompile(`(?i)\bon update (\S+)`)
+
+func (in *InformationSchemaIntrospector) columns(db *sql.DB, schema string, tables map[string]*CreateTableStmt, opts Options) error {
+	query := "SELECT TABLE_NAME, COLUMN_NAME, COLUMN_TYPE, '', IS_NULLABLE, COLUMN_DEFAULT, EXTRA, COLUMN_COMMENT, " +
+		"CHARACTER_SET_NAME, COLLATION_NAME, NULL, NULL, NULL FROM information_schema.COLUMNS " +
+		"WHERE TABLE_SCHEMA = ? ORDER BY TABLE_NAME, ORDINAL_POSITION"
+	if in.Dialect == PostgreSQL {
+		query = "SELECT TABLE_NAME, COLUMN_NAME, DATA_TYPE, UDT_NAME, IS_NULLABLE, COLUMN_DEFAULT, IS_IDENTITY, '', " +
+			"NULL, NULL, CHARACTER_MAXIMUM_LENGTH, NUMERIC_PRECISION, NUMERIC_SCALE FROM information_schema.COLUMNS " +
+			"WHERE TABLE_SCHEMA = $1 ORDER BY TABLE_NAME, ORDINAL_POSITION"
+	}
+	rows, err := db.Query(query, schema)
+	if err != nil {
+		return fmt.Errorf("read information_schema.COLUMNS failed, err: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			table, name, typeName, udtName, nullable, extra, comment string
+			defaultValue, charset, collation                         sql.NullString
+			length, precision, scale                                 sql.NullInt64
+		)
+		if err := rows.Scan(&table, &name, &typeName, &udtName, &nullable, &defaultValue, &extra, &comment,
+			&charset, &collation, &length, &precision, &scale); err != nil {
+			return err
+		}
+		stmt := tables[introspectedIdent(table, opts)]
+		if stmt == nil {
+			continue
+		}
+		column := &ColumnDef{
+			Name:      introspectedIdent(name, opts),
+			Comment:   comment,
+			NotNull:   strings.EqualFold(nullable, "NO"),
+			Charset:   charset.String,
+			Collation: collation.String,
+		}
+		if in.Dialect == PostgreSQL {
+			column.Type = postgresDataType(typeName, udtName, length, precision, scale)
+			if defaultValue.Valid {
+				column.Default = &defaultValue.String
+				column.AutoIncrement = isSequenceDefault(defaultValue.String)
+			}
+			column.AutoIncrement = column.AutoIncrement || strings.EqualFold(extra, "YES")
+		} else {
+			if column.Type, err = parseTypeName(typeName, Options{Dialect: MySQL}); err != nil {
+				return fmt.Errorf("column %s.%s: %v", table, name, err)
+			}
+			lower := strings.ToLower(typeName)
+			column.Unsigned = strings.Contains(lower, " unsigned")
+			column.Zerofill = strings.Contains(lower, " zerofill")
+			if defaultValue.Valid {
+				value := mysqlDefault(defaultValue.String, extra)
+				column.Default = &value
+			}
+			column.AutoIncrement = strings.Contains(strings.ToLower(extra), "auto_increment")
+			if matches := mysqlOnUpdate.FindStringSubmatch(extra); matches != nil {
+				column.OnUpdate = matches[1]
+			}
+		}
+		stmt.Columns = append(stmt.Columns, column)
+	}
+	return rows.Err()
+}
+
+// postgresDataType builds the type of a column of PostgreSQL, arrays and user defined types
+// are reported by the name of their underlying type, e.g. _int4 for integer[]
+func postgresDataType(typeName, udtName string, length, precision, scale sql.NullInt64) *DataType {
+	dataType := &DataType{Name: typeName}
+	switch strings.ToUpper(typeName) {
+	case "ARRAY":
+		dataType.Name, dataType.Array = strings.TrimPrefix(udtName, "_"), true
+	case "USER-DEFINED":
+		dataType.Name = udtName
+	case "NUMERIC", "DECIMAL":
+		if precision.Valid {
+			dataType.Args = append(dataType.Args, strconv.FormatInt(precision.Int64, 10))
+			if scale.Valid {
+				dataType.Args = append(dataType.Args, strconv.FormatInt(scale.Int64, 10))
+			}
+		}
+	}
+	if length.Valid {
+		dataType.Args = append(dataType.Args, strconv.FormatInt(length.Int64, 10))
+	}
+	return dataType
+}
+
+// mysqlDefault returns the default of a column of MySQL as it is written in the statement,
+// MySQL reports the literals without quotes and the expressions as DEFAULT_GENERATED
+func mysqlDefault(value, extra string) string {
+	if strings.Contains(strings.ToUpper(extra), "DEFAULT_GENERATED") ||
+		strings.HasPrefix(strings.ToUpper(value), "CURRENT_TIMESTAMP") {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	return "'" + strings.Replace(value, "'", "''", -1) + "'"
+}
+
+// keys reads the primary keys and the indexes from STATISTICS of MySQL, or the primary keys and
+// unique constraints from TABLE_CONSTRAINTS of PostgreSQL
+func (in *InformationSchemaIntrospector) keys(db *sql.DB, schema string, tables map[string]*CreateTableStmt, opts Options) error {
+	query := "SELECT TABLE_NAME, INDEX_NAME, CASE WHEN INDEX_NAME = 'PRIMARY' THEN 'PRIMARY KEY' " +
+		"WHEN NON_UNIQUE = 0 THEN 'UNIQUE' ELSE INDEX_TYPE END, COLUMN_NAME FROM information_schema.STATISTICS " +
+		"WHERE TABLE_SCHEMA = ? ORDER BY TABLE_NAME, INDEX_NAME <> 'PRIMARY', INDEX_NAME, SEQ_IN_INDEX"
+	if in.Dialect == PostgreSQL {
+		query = "SELECT tc.TABLE_NAME, tc.CONSTRAINT_NAME, tc.CONSTRAINT_TYPE, kcu.COLUMN_NAME " +
+			"FROM information_schema.TABLE_CONSTRAINTS tc JOIN information_schema.KEY_COLUMN_USAGE kcu " +
+			"ON kcu.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA AND kcu.CONSTRAINT_NAME = tc.CONSTRAINT_NAME " +
+			"AND kcu.TABLE_NAME = tc.TABLE_NAME WHERE tc.TABLE_SCHEMA = $1 AND tc.CONSTRAINT_TYPE IN ('PRIMARY KEY', 'UNIQUE') " +
+			"ORDER BY tc.TABLE_NAME, tc.CONSTRAINT_TYPE <> 'PRIMARY KEY', tc.CONSTRAINT_NAME, kcu.ORDINAL_POSITION"
+	}
+	rows, err := db.Query(query, schema)
+	if err != nil {
+		return fmt.Errorf("read the keys failed, err: %v", err)
+	}
+	defer rows.Close()
+
+	var last *ConstraintDef
+	for rows.Next() {
+		var (
+			table, name, kind string
+			column            sql.NullString
+		)
+		if err := rows.Scan(&table, &name, &kind, &column); err != nil {
+			return err
+		}
+		stmt := tables[introspectedIdent(table, opts)]
+		// the functional key parts have no column
+		if stmt == nil || !column.Valid {
+			continue
+		}
+		constraint := &ConstraintDef{Kind: IndexNormal, Name: introspectedIdent(name, opts)}
+		switch strings.ToUpper(kind) {
+		case "PRIMARY KEY":
+			constraint.Kind = IndexPrimary
+			// the primary key of MySQL is always named PRIMARY
+			if in.Dialect == MySQL {
+				constraint.Name = ""
+			}
+		case "UNIQUE":
+			constraint.Kind = IndexUnique
+		case "FULLTEXT":
+			constraint.Kind = IndexFulltext
+		case "SPATIAL":
+			constraint.Kind = IndexSpatial
+		}
+		if n := len(stmt.Constraints); n > 0 && stmt.Constraints[n-1] == last &&
+			last.Kind == constraint.Kind && last.Name == constraint.Name {
+			last.Columns = append(last.Columns, introspectedIdent(column.String, opts))
+			continue
+		}
+		constraint.Columns = []string{introspectedIdent(column.String, opts)}
+		stmt.Constraints = append(stmt.Constraints, constraint)
+		last = constraint
+	}
+	return rows.Err()
+}
+
+// foreignKeys reads the foreign keys from KEY_COLUMN_USAGE and REFERENTIAL_CONSTRAINTS, PostgreSQL
+// reports the referenced columns as the key columns of the referenced unique constraint
+func (in *InformationSchemaIntrospector) foreignKeys(db *sql.DB, schema string, tables map[string]*CreateTableStmt, opts Options) error {
+	query := "SELECT kcu.TABLE_NAME, kcu.CONSTRAINT_NAME, kcu.COLUMN_NAME, kcu.REFERENCED_TABLE_NAME, " +
+		"kcu.REFERENCED_COLUMN_NAME, rc.UPDATE_RULE, rc.DELETE_RULE FROM information_schema.KEY_COLUMN_USAGE kcu " +
+		"JOIN information_schema.REFERENTIAL_CONSTRAINTS rc ON rc.CONSTRAINT_SCHEMA = kcu.CONSTRAINT_SCHEMA " +
+		"AND rc.CONSTRAINT_NAME = kcu.CONSTRAINT_NAME AND rc.TABLE_NAME = kcu.TABLE_NAME " +
+		"WHERE kcu.TABLE_SCHEMA = ? AND kcu.REFERENCED_TABLE_NAME IS NOT NULL " +
+		"ORDER BY kcu.TABLE_NAME, kcu.CONSTRAINT_NAME, kcu.ORDINAL_POSITION"
+	if in.Dialect == PostgreSQL {
+		query = "SELECT kcu.TABLE_NAME, kcu.CONSTRAINT_NAME, kcu.COLUMN_NAME, ref.TABLE_NAME, ref.COLUMN_NAME, " +
+			"rc.UPDATE_RULE, rc.DELETE_RULE FROM information_schema.REFERENTIAL_CONSTRAINTS rc " +
+			"JOIN information_schema.KEY_COLUMN_USAGE kcu ON kcu.CONSTRAINT_SCHEMA = rc.CONSTRAINT_SCHEMA " +
+			"AND kcu.CONSTRAINT_NAME = rc.CONSTRAINT_NAME JOIN information_schema.KEY_COLUMN_USAGE ref " +
+			"ON ref.CONSTRAINT_SCHEMA = rc.UNIQUE_CONSTRAINT_SCHEMA AND ref.CONSTRAINT_NAME = rc.UNIQUE_CONSTRAINT_NAME " +
+			"AND ref.ORDINAL_POSITION = kcu.POSITION_IN_UNIQUE_CONSTRAINT WHERE kcu.TABLE_SCHEMA = $1 " +
+			"ORDER BY kcu.TABLE_NAME, kcu.CONSTRAINT_NAME, kcu.ORDINAL_POSITION"
+	}
+	rows, err := db.Query(query, schema)
+	if err != nil {
+		return fmt.Errorf("read the foreign keys failed, err: %v", err)
+	}
+	defer rows.Close()
+
+	var last *ForeignKeyDef
+	for rows.Next() {
+		var table, name, column, refTable, refColumn, onUpdate, onDelete string
+		if err := rows.Scan(&table, &name, &column, &refTable, &refColumn, &onUpdate, &onDelete); err != nil {
+			return err
+		}
+		stmt := tables[introspectedIdent(table, opts)]
+		if stmt == nil {
+			continue
+		}
+		name = introspectedIdent(name, opts)
+		if n := len(stmt.ForeignKeys); n == 0 || stmt.ForeignKeys[n-1] != last || last.Name != name {
+			last = &ForeignKeyDef{
+				Name:     name,
+				RefTable: introspectedIdent(refTable, opts),
+				OnDelete: referenceAction(onDelete),
+				OnUpdate: referenceAction(onUpdate),
+			}
+			stmt.ForeignKeys = append(stmt.ForeignKeys, last)
+		}
+		last.Columns = append(last.Columns, introspectedIdent(column, opts))
+		last.RefColumns = append(last.RefColumns, introspectedIdent(refColumn, opts))
+	}
+	return rows.Err()
+}
+
+// referenceAction returns the action of ON DELETE or ON UPDATE, NO ACTION is the default
+// one which is reported when the statement does not declare any
+func referenceAction(action string) string {
+	if strings.EqualFold(action, "NO ACTION") {
+		return ""
+	}
+	return strings.ToUpper(action)
+}
+
+// introspectedIdent folds the name to lower case as the DDL parser does when FoldIdentCase is set
+func introspectedIdent(name string, opts Options) string {
+	if !opts.FoldIdentCase {
+		return name
+	}
+	return strings.ToLower(name)
+}
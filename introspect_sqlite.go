@@ -0,0 +1,285 @@
+package sqlconverter
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// sqliteFileHeader starts every sqlite database file, see https://www.sqlite.org/fileformat.html
+const sqliteFileHeader = "SQLite format 3\x00"
+
+// sqliteWithoutRowID matches the WITHOUT ROWID option at the end of the sql of a table in sqlite_master
+var sqliteWithoutRowID = regexp.MustCompile(`(?i)\bWITHOUT\s+ROWID\b[\s,\w]*$`)
+
+// isSQLiteFile reports whether the file is a sqlite database
+func isSQLiteFile(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	header := make([]byte, len(sqliteFileHeader))
+	if _, err := io.ReadFull(f, header); err != nil {
+		return false
+	}
+	return bytes.Equal(header, []byte(sqliteFileHeader))
+}
+
+// readSQLiteFile reads the tables of the sqlite database file, which is opened read only by the sqlite driver,
+// e.g. modernc.org/sqlite
+func readSQLiteFile(path string, opts Options) (*sqlSource, error) {
+	// the path is escaped in the uri, e.g. ? and # are part of the file name
+	dsn := &url.URL{Scheme: "file", Path: filepath.ToSlash(path), RawQuery: "mode=ro"}
+	db, err := sql.Open("sqlite", dsn.String())
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	tables, err := introspectSQLite(db, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &sqlSource{Path: path, Tables: tables}, nil
+}
+
+// introspectSQLite builds the tables of the database from sqlite_master and the table pragmas,
+// the tables are the same as the ones parsed from their CREATE TABLE statements
+func introspectSQLite(db *sql.DB, opts Options) ([]*TableStruct, error) {
+	rows, err := db.Query("SELECT name, sql FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite\\_%' ESCAPE '\\' ORDER BY rowid")
+	if err != nil {
+		return nil, fmt.Errorf("read sqlite_master failed, err: %v", err)
+	}
+	type master struct {
+		name string
+		sql  string
+	}
+	var masters []master
+	for rows.Next() {
+		var (
+			m    master
+			text sql.NullString
+		)
+		if err := rows.Scan(&m.name, &text); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		m.sql = text.String
+		masters = append(masters, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var res []*TableStruct
+	for _, m := range masters {
+		stmt, err := sqliteCreateTable(db, m.name, m.sql, opts)
+		if err != nil {
+			return nil, fmt.Errorf("table %s: %v", m.name, err)
+		}
+		res = append(res, stmt.toTableStruct())
+	}
+	return res, nil
+}
+
+// sqliteCreateTable rebuilds the CREATE TABLE statement of the table from its pragmas
+func sqliteCreateTable(db *sql.DB, table, text string, opts Options) (*CreateTableStmt, error) {
+	stmt := &CreateTableStmt{Table: introspectedIdent(table, opts)}
+	if sqliteWithoutRowID.MatchString(strings.TrimRight(strings.TrimSpace(text), ";")) {
+		stmt.Options = append(stmt.Options, &TableOption{Name: "WITHOUT ROWID"})
+	}
+	if err := sqliteColumns(db, stmt, table, opts); err != nil {
+		return nil, err
+	}
+	if err := sqliteIndexes(db, stmt, table, opts); err != nil {
+		return nil, err
+	}
+	if err := sqliteForeignKeys(db, stmt, table, opts); err != nil {
+		return nil, err
+	}
+	sqliteRowIDAlias(stmt)
+	return stmt, nil
+}
+
+// sqliteColumns reads the columns and the primary key by PRAGMA table_info
+func sqliteColumns(db *sql.DB, stmt *CreateTableStmt, table string, opts Options) error {
+	rows, err := db.Query("SELECT name, type, \"notnull\", dflt_value, pk FROM pragma_table_info(?)", table)
+	if err != nil {
+		return fmt.Errorf("read table_info failed, err: %v", err)
+	}
+	defer rows.Close()
+
+	// the columns of the primary key ordered by their position in it
+	pk := make(map[int]string)
+	for rows.Next() {
+		var (
+			name, typeName string
+			notNull        bool
+			defaultValue   sql.NullString
+			position       int
+		)
+		if err := rows.Scan(&name, &typeName, &notNull, &defaultValue, &position); err != nil {
+			return err
+		}
+		column := &ColumnDef{Name: introspectedIdent(name, opts), NotNull: notNull}
+		if column.Type, err = parseTypeName(typeName, Options{Dialect: SQLite}); err != nil {
+			return fmt.Errorf("column %s: %v", name, err)
+		}
+		// a column without a declared type is kept as the parser does, e.g. a in CREATE TABLE t (a, b int)
+		if column.Type == nil {
+			column.Type = &DataType{}
+		}
+		if defaultValue.Valid {
+			column.Default = &defaultValue.String
+		}
+		if position > 0 {
+			pk[position] = column.Name
+		}
+		stmt.Columns = append(stmt.Columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(pk) > 0 {
+		primary := &ConstraintDef{Kind: IndexPrimary}
+		for i := 1; i <= len(pk); i++ {
+			primary.Columns = append(primary.Columns, pk[i])
+		}
+		stmt.Constraints = append(stmt.Constraints, primary)
+	}
+	return nil
+}
+
+// sqliteIndexes reads the unique constraints and the indexes in the order they are created,
+// the automatic index of the primary key is skipped
+func sqliteIndexes(db *sql.DB, stmt *CreateTableStmt, table string, opts Options) error {
+	rows, err := db.Query("SELECT l.name, l.\"unique\", l.origin FROM sqlite_master m "+
+		"JOIN pragma_index_list(?) l ON l.name = m.name WHERE m.type = 'index' ORDER BY m.rowid", table)
+	if err != nil {
+		return fmt.Errorf("read index_list failed, err: %v", err)
+	}
+	type index struct {
+		name   string
+		unique bool
+		origin string
+	}
+	var indexes []index
+	for rows.Next() {
+		var i index
+		if err := rows.Scan(&i.name, &i.unique, &i.origin); err != nil {
+			rows.Close()
+			return err
+		}
+		indexes = append(indexes, i)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, i := range indexes {
+		if i.origin == "pk" {
+			continue
+		}
+		columns, err := sqliteIndexColumns(db, i.name, opts)
+		if err != nil {
+			return err
+		}
+		if len(columns) == 0 {
+			continue
+		}
+		constraint := &ConstraintDef{Kind: IndexNormal, Columns: columns}
+		if i.unique {
+			constraint.Kind = IndexUnique
+		}
+		// the unique constraints are indexed by automatic indexes, which are not named in the statement
+		if !strings.HasPrefix(i.name, "sqlite_autoindex_") {
+			constraint.Name = introspectedIdent(i.name, opts)
+		}
+		stmt.Constraints = append(stmt.Constraints, constraint)
+	}
+	return nil
+}
+
+// sqliteIndexColumns reads the columns of the index, the expressions of it are skipped
+func sqliteIndexColumns(db *sql.DB, index string, opts Options) ([]string, error) {
+	rows, err := db.Query("SELECT name FROM pragma_index_info(?) ORDER BY seqno", index)
+	if err != nil {
+		return nil, fmt.Errorf("read index_info failed, err: %v", err)
+	}
+	defer rows.Close()
+
+	var res []string
+	for rows.Next() {
+		var name sql.NullString
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		if name.Valid {
+			res = append(res, introspectedIdent(name.String, opts))
+		}
+	}
+	return res, rows.Err()
+}
+
+// sqliteForeignKeys reads the foreign keys by PRAGMA foreign_key_list, which lists them
+// in the reverse order of the statement
+func sqliteForeignKeys(db *sql.DB, stmt *CreateTableStmt, table string, opts Options) error {
+	rows, err := db.Query("SELECT id, \"table\", \"from\", \"to\", on_update, on_delete "+
+		"FROM pragma_foreign_key_list(?) ORDER BY id, seq", table)
+	if err != nil {
+		return fmt.Errorf("read foreign_key_list failed, err: %v", err)
+	}
+	defer rows.Close()
+
+	keys := make(map[int]*ForeignKeyDef)
+	for rows.Next() {
+		var (
+			id                 int
+			refTable, from     string
+			to                 sql.NullString
+			onUpdate, onDelete string
+		)
+		if err := rows.Scan(&id, &refTable, &from, &to, &onUpdate, &onDelete); err != nil {
+			return err
+		}
+		key, exist := keys[id]
+		if !exist {
+			key = &ForeignKeyDef{
+				RefTable: introspectedIdent(refTable, opts),
+				OnDelete: referenceAction(onDelete),
+				OnUpdate: referenceAction(onUpdate),
+			}
+			keys[id] = key
+		}
+		key.Columns = append(key.Columns, introspectedIdent(from, opts))
+		// the referenced columns are omitted when the foreign key references the primary key
+		if to.Valid {
+			key.RefColumns = append(key.RefColumns, introspectedIdent(to.String, opts))
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var ids []int
+	for id := range keys {
+		ids = append(ids, id)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(ids)))
+	for _, id := range ids {
+		stmt.ForeignKeys = append(stmt.ForeignKeys, keys[id])
+	}
+	return nil
+}
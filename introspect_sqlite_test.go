@@ -0,0 +1,110 @@
+package sqlconverter
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	_ "modernc.org/sqlite"
+)
+
+// createSQLiteFile creates a sqlite database file in a temporary directory by the statements
+func createSQLiteFile(t *testing.T, sqls []string) string {
+	path := filepath.Join(t.TempDir(), "test.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	for _, s := range sqls {
+		if _, err := db.Exec(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+func TestIntrospectSQLite(t *testing.T) {
+	cases := [][]string{
+		{
+			"CREATE TABLE users (id INTEGER PRIMARY KEY, name VARCHAR(64) NOT NULL DEFAULT 'anonymous', " +
+				"email TEXT UNIQUE, score DECIMAL(10, 2), created_at DATETIME DEFAULT CURRENT_TIMESTAMP, " +
+				"age UNSIGNED BIG INT, a INT, b INT, UNIQUE (a, b))",
+			"CREATE INDEX idx_name ON users (name, created_at)",
+			"CREATE INDEX idx_lower_email ON users (lower(email), score)",
+		},
+		{
+			"CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, code TEXT, UNIQUE (code))",
+			"CREATE TABLE orders (user_id INT REFERENCES users ON DELETE CASCADE, code TEXT, year INT, " +
+				"FOREIGN KEY (code) REFERENCES users (code) ON UPDATE SET NULL, PRIMARY KEY (user_id, year)) WITHOUT ROWID",
+			"CREATE TABLE kv (k TEXT PRIMARY KEY, v BLOB)",
+		},
+		{
+			"CREATE TABLE Users (ID INTEGER NOT NULL, Name TEXT, PRIMARY KEY (ID DESC))",
+			"CREATE UNIQUE INDEX UK_Name ON Users (Name)",
+		},
+		{
+			"CREATE TABLE t (a, b INT, c NOT NULL DEFAULT 1)",
+		},
+	}
+
+	for i, sqls := range cases {
+		for _, foldCase := range []bool{false, true} {
+			t.Run(fmt.Sprintf("Case %d, fold case %v", i, foldCase), func(t *testing.T) {
+				opts := Options{Dialect: SQLite, FoldIdentCase: foldCase}
+				var expected Schema
+				for _, s := range sqls {
+					stmt, err := parseStatement(s, opts)
+					if err != nil {
+						t.Fatal(err)
+					}
+					expected.Apply(stmt)
+				}
+
+				source, err := readSQLiteFile(createSQLiteFile(t, sqls), opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				assert.Empty(t, source.Sqls)
+				assert.Equal(t, expected.Tables, source.Tables)
+			})
+		}
+	}
+}
+
+func TestParseSQLiteFile(t *testing.T) {
+	var out, info bytes.Buffer
+	stdout, stderr = &out, &info
+	defer func() {
+		stdout, stderr = os.Stdout, os.Stderr
+	}()
+
+	// the file name contains the special characters of a uri
+	path := filepath.Join(t.TempDir(), "test?#%.db")
+	if err := os.Rename(createSQLiteFile(t, []string{
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL, score REAL, extra)",
+	}), path); err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, isSQLiteFile(path))
+	assert.False(t, isSQLiteFile("./testdata/mysqldump.sql"))
+
+	parser := &CreateTableSQLParser{
+		SqlFile:    path,
+		OutputFile: StdoutPath,
+	}
+	if err := parser.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, SQLite, parser.Dialect)
+	assert.Equal(t, "package main\n\n// table: users\ntype Users struct {\n"+
+		"\tID    int64       `json:\"id\" db:\"id\"`\n"+
+		"\tName  string      `json:\"name\" db:\"name\"`\n"+
+		"\tScore float64     `json:\"score\" db:\"score\"`\n"+
+		"\tExtra interface{} `json:\"extra\" db:\"extra\"`\n}\n", out.String())
+	assert.Equal(t, fmt.Sprintf("Table: users, from: %s\n", path), info.String())
+}
@@ -0,0 +1,185 @@
+package sqlconverter
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// openInformationSchema opens a sqlite database standing in for a server, whose attached
+// information_schema has the tables and columns read by InformationSchemaIntrospector
+func openInformationSchema(t *testing.T, inserts []string) *sql.DB {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the attached database belongs to the connection
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	sqls := append([]string{
+		"ATTACH DATABASE ':memory:' AS information_schema",
+		"CREATE TABLE information_schema.TABLES (TABLE_SCHEMA TEXT, TABLE_NAME TEXT, TABLE_TYPE TEXT, TABLE_COMMENT TEXT)",
+		"CREATE TABLE information_schema.COLUMNS (TABLE_SCHEMA TEXT, TABLE_NAME TEXT, COLUMN_NAME TEXT, ORDINAL_POSITION INT, " +
+			"COLUMN_DEFAULT TEXT, IS_NULLABLE TEXT, DATA_TYPE TEXT, UDT_NAME TEXT, COLUMN_TYPE TEXT, EXTRA TEXT, IS_IDENTITY TEXT, " +
+			"COLUMN_COMMENT TEXT, CHARACTER_SET_NAME TEXT, COLLATION_NAME TEXT, CHARACTER_MAXIMUM_LENGTH INT, " +
+			"NUMERIC_PRECISION INT, NUMERIC_SCALE INT)",
+		"CREATE TABLE information_schema.STATISTICS (TABLE_SCHEMA TEXT, TABLE_NAME TEXT, INDEX_NAME TEXT, NON_UNIQUE INT, " +
+			"SEQ_IN_INDEX INT, COLUMN_NAME TEXT, INDEX_TYPE TEXT)",
+		"CREATE TABLE information_schema.TABLE_CONSTRAINTS (CONSTRAINT_SCHEMA TEXT, CONSTRAINT_NAME TEXT, TABLE_SCHEMA TEXT, " +
+			"TABLE_NAME TEXT, CONSTRAINT_TYPE TEXT)",
+		"CREATE TABLE information_schema.KEY_COLUMN_USAGE (CONSTRAINT_SCHEMA TEXT, CONSTRAINT_NAME TEXT, TABLE_SCHEMA TEXT, " +
+			"TABLE_NAME TEXT, COLUMN_NAME TEXT, ORDINAL_POSITION INT, POSITION_IN_UNIQUE_CONSTRAINT INT, " +
+			"REFERENCED_TABLE_NAME TEXT, REFERENCED_COLUMN_NAME TEXT)",
+		"CREATE TABLE information_schema.REFERENTIAL_CONSTRAINTS (CONSTRAINT_SCHEMA TEXT, CONSTRAINT_NAME TEXT, " +
+			"UNIQUE_CONSTRAINT_SCHEMA TEXT, UNIQUE_CONSTRAINT_NAME TEXT, UPDATE_RULE TEXT, DELETE_RULE TEXT, TABLE_NAME TEXT)",
+	}, inserts...)
+	for _, s := range sqls {
+		if _, err := db.Exec(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func TestIntrospectMySQL(t *testing.T) {
+	db := openInformationSchema(t, []string{
+		"INSERT INTO information_schema.TABLES VALUES ('shop', 'users', 'BASE TABLE', 'users'), " +
+			"('shop', 'orders', 'BASE TABLE', ''), ('shop', 'v_orders', 'VIEW', ''), ('other', 'logs', 'BASE TABLE', '')",
+		"INSERT INTO information_schema.COLUMNS (TABLE_SCHEMA, TABLE_NAME, COLUMN_NAME, ORDINAL_POSITION, COLUMN_DEFAULT, " +
+			"IS_NULLABLE, COLUMN_TYPE, EXTRA, COLUMN_COMMENT) VALUES " +
+			"('shop', 'users', 'id', 1, NULL, 'NO', 'bigint(20) unsigned', 'auto_increment', 'primary key'), " +
+			"('shop', 'users', 'name', 2, 'anonymous', 'NO', 'varchar(64)', '', 'user''s name'), " +
+			"('shop', 'users', 'created_at', 3, 'CURRENT_TIMESTAMP', 'NO', 'timestamp', " +
+			"'DEFAULT_GENERATED on update CURRENT_TIMESTAMP', ''), " +
+			"('shop', 'users', 'score', 4, '0', 'YES', 'decimal(10,2)', '', ''), " +
+			"('shop', 'orders', 'user_id', 2, NULL, 'NO', 'bigint(20) unsigned', '', ''), " +
+			"('shop', 'orders', 'id', 1, NULL, 'NO', 'int', 'auto_increment', ''), " +
+			"('other', 'logs', 'id', 1, NULL, 'NO', 'int', '', '')",
+		"INSERT INTO information_schema.STATISTICS VALUES " +
+			"('shop', 'users', 'uk_name', 0, 1, 'name', 'BTREE'), " +
+			"('shop', 'users', 'idx_created', 1, 2, 'score', 'BTREE'), " +
+			"('shop', 'users', 'idx_created', 1, 1, 'created_at', 'BTREE'), " +
+			"('shop', 'users', 'PRIMARY', 0, 1, 'id', 'BTREE'), " +
+			"('shop', 'users', 'idx_lower_name', 1, 1, NULL, 'BTREE'), " +
+			"('shop', 'orders', 'PRIMARY', 0, 1, 'id', 'BTREE'), " +
+			"('shop', 'orders', 'fk_user', 1, 1, 'user_id', 'BTREE')",
+		"INSERT INTO information_schema.KEY_COLUMN_USAGE (CONSTRAINT_SCHEMA, CONSTRAINT_NAME, TABLE_SCHEMA, TABLE_NAME, " +
+			"COLUMN_NAME, ORDINAL_POSITION, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME) VALUES " +
+			"('shop', 'PRIMARY', 'shop', 'users', 'id', 1, NULL, NULL), " +
+			"('shop', 'fk_orders_user', 'shop', 'orders', 'user_id', 1, 'users', 'id')",
+		"INSERT INTO information_schema.REFERENTIAL_CONSTRAINTS (CONSTRAINT_SCHEMA, CONSTRAINT_NAME, UPDATE_RULE, " +
+			"DELETE_RULE, TABLE_NAME) VALUES ('shop', 'fk_orders_user', 'NO ACTION', 'CASCADE', 'orders')",
+	})
+
+	var expected Schema
+	for _, s := range []string{
+		"CREATE TABLE orders (id int NOT NULL AUTO_INCREMENT, user_id bigint(20) unsigned NOT NULL, PRIMARY KEY (id), " +
+			"KEY fk_user (user_id), CONSTRAINT fk_orders_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE)",
+		"CREATE TABLE users (id bigint(20) unsigned NOT NULL AUTO_INCREMENT COMMENT 'primary key', " +
+			"name varchar(64) NOT NULL DEFAULT 'anonymous' COMMENT 'user''s name', " +
+			"created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP, score decimal(10,2) DEFAULT 0, " +
+			"PRIMARY KEY (id), KEY idx_created (created_at, score), UNIQUE KEY uk_name (name)) COMMENT='users'",
+	} {
+		stmt, err := parseStatement(s, Options{Dialect: MySQL})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected.Apply(stmt)
+	}
+
+	introspector := &InformationSchemaIntrospector{Dialect: MySQL, Schema: "shop"}
+	actual, err := introspector.Introspect(db, Options{Dialect: MySQL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, expected.Tables, actual)
+}
+
+func TestIntrospectPostgreSQL(t *testing.T) {
+	db := openInformationSchema(t, []string{
+		"INSERT INTO information_schema.TABLES (TABLE_SCHEMA, TABLE_NAME, TABLE_TYPE) VALUES " +
+			"('public', 'users', 'BASE TABLE'), ('public', 'orders', 'BASE TABLE')",
+		"INSERT INTO information_schema.COLUMNS (TABLE_SCHEMA, TABLE_NAME, COLUMN_NAME, ORDINAL_POSITION, COLUMN_DEFAULT, " +
+			"IS_NULLABLE, DATA_TYPE, UDT_NAME, IS_IDENTITY, CHARACTER_MAXIMUM_LENGTH, NUMERIC_PRECISION, NUMERIC_SCALE) VALUES " +
+			"('public', 'users', 'id', 1, 'nextval(''users_id_seq''::regclass)', 'NO', 'integer', 'int4', 'NO', NULL, 32, 0), " +
+			"('public', 'users', 'name', 2, NULL, 'NO', 'character varying', 'varchar', 'NO', 64, NULL, NULL), " +
+			"('public', 'users', 'tags', 3, NULL, 'YES', 'ARRAY', '_text', 'NO', NULL, NULL, NULL), " +
+			"('public', 'users', 'mood', 4, NULL, 'YES', 'USER-DEFINED', 'mood', 'NO', NULL, NULL, NULL), " +
+			"('public', 'orders', 'id', 1, NULL, 'NO', 'bigint', 'int8', 'YES', NULL, 64, 0), " +
+			"('public', 'orders', 'user_id', 2, NULL, 'YES', 'integer', 'int4', 'NO', NULL, 32, 0), " +
+			"('public', 'orders', 'price', 3, NULL, 'YES', 'numeric', 'numeric', 'NO', NULL, 10, 2)",
+		"INSERT INTO information_schema.TABLE_CONSTRAINTS VALUES " +
+			"('public', 'users_pkey', 'public', 'users', 'PRIMARY KEY'), " +
+			"('public', 'users_name_key', 'public', 'users', 'UNIQUE'), " +
+			"('public', 'orders_pkey', 'public', 'orders', 'PRIMARY KEY'), " +
+			"('public', 'orders_user_id_fkey', 'public', 'orders', 'FOREIGN KEY'), " +
+			"('public', 'orders_price_check', 'public', 'orders', 'CHECK')",
+		"INSERT INTO information_schema.KEY_COLUMN_USAGE (CONSTRAINT_SCHEMA, CONSTRAINT_NAME, TABLE_SCHEMA, TABLE_NAME, " +
+			"COLUMN_NAME, ORDINAL_POSITION, POSITION_IN_UNIQUE_CONSTRAINT) VALUES " +
+			"('public', 'users_pkey', 'public', 'users', 'id', 1, NULL), " +
+			"('public', 'users_name_key', 'public', 'users', 'name', 1, NULL), " +
+			"('public', 'orders_pkey', 'public', 'orders', 'id', 1, NULL), " +
+			"('public', 'orders_user_id_fkey', 'public', 'orders', 'user_id', 1, 1)",
+		"INSERT INTO information_schema.REFERENTIAL_CONSTRAINTS (CONSTRAINT_SCHEMA, CONSTRAINT_NAME, UNIQUE_CONSTRAINT_SCHEMA, " +
+			"UNIQUE_CONSTRAINT_NAME, UPDATE_RULE, DELETE_RULE) VALUES " +
+			"('public', 'orders_user_id_fkey', 'public', 'users_pkey', 'NO ACTION', 'SET NULL')",
+	})
+
+	expected := []*TableStruct{
+		{
+			TableName: "orders",
+			Fields: []*FieldInfo{
+				{FieldName: "id", FieldType: "bigint", AutoIncrement: true},
+				{FieldName: "user_id", FieldType: "integer", Nullable: true},
+				{FieldName: "price", FieldType: "numeric", Nullable: true, Precision: 10, Scale: 2},
+			},
+			PrimaryKey: &IndexInfo{Name: "orders_pkey", Kind: IndexPrimary, Columns: []string{"id"}},
+			ForeignKeys: []*ForeignKeyInfo{
+				{Name: "orders_user_id_fkey", Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}, OnDelete: "SET NULL"},
+			},
+		},
+		{
+			TableName: "users",
+			Fields: []*FieldInfo{
+				{FieldName: "id", FieldType: "integer", AutoIncrement: true, Default: stringPtr("nextval('users_id_seq'::regclass)")},
+				{FieldName: "name", FieldType: "character varying", Length: 64},
+				{FieldName: "tags", FieldType: "text", Array: true, Nullable: true},
+				{FieldName: "mood", FieldType: "mood", Nullable: true},
+			},
+			PrimaryKey: &IndexInfo{Name: "users_pkey", Kind: IndexPrimary, Columns: []string{"id"}},
+			Indexes: []*IndexInfo{
+				{Name: "users_name_key", Kind: IndexUnique, Columns: []string{"name"}},
+			},
+		},
+	}
+
+	introspector := &InformationSchemaIntrospector{Dialect: PostgreSQL, Schema: "public"}
+	actual, err := introspector.Introspect(db, Options{Dialect: PostgreSQL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, expected, actual)
+}
+
+type stubIntrospector struct{}
+
+func (stubIntrospector) Introspect(db *sql.DB, opts Options) ([]*TableStruct, error) {
+	return []*TableStruct{{TableName: "orders"}}, nil
+}
+
+func TestRegisterIntrospector(t *testing.T) {
+	_, err := introspectDSN("sqlite", ":memory:", Options{Dialect: SQLServer})
+	assert.Error(t, err)
+
+	RegisterIntrospector(SQLServer, stubIntrospector{})
+	defer RegisterIntrospector(SQLServer, nil)
+	tables, err := introspectDSN("sqlite", ":memory:", Options{Dialect: SQLServer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []*TableStruct{{TableName: "orders"}}, tables)
+}
@@ -0,0 +1,274 @@
+package sqlconverter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IRVersion is the version of the schema IR written by NewIR, ReadIR rejects the other versions
+const IRVersion = 1
+
+// IR is the canonical intermediate representation of a schema, which is written as JSON by the inspect
+// command and read back as input from .json files, so other tools can consume or produce a schema without DDL.
+// The JSON names are stable within a version, fields which are empty or false are omitted
+type IR struct {
+	Version   int           `json:"version"`           // IRVersion
+	Dialect   Dialect       `json:"dialect,omitempty"` // the dialect of the column types, e.g. MYSQL
+	Tables    []*IRTable    `json:"tables"`
+	Relations []*IRRelation `json:"relations,omitempty"` // derived from the foreign keys, ignored when the IR is read
+}
+
+type IRTable struct {
+	Name        string          `json:"name"`
+	Comment     string          `json:"comment,omitempty"`
+	Columns     []*IRColumn     `json:"columns"`
+	PrimaryKey  *IRIndex        `json:"primary_key,omitempty"`
+	Indexes     []*IRIndex      `json:"indexes,omitempty"` // the unique constraints and secondary indexes
+	ForeignKeys []*IRForeignKey `json:"foreign_keys,omitempty"`
+	Source      string          `json:"source,omitempty"` // the file or database the table is read from
+}
+
+type IRColumn struct {
+	Name          string  `json:"name"`
+	Type          string  `json:"type"`            // the sql type without arguments, e.g. VARCHAR, empty when it is not declared
+	Array         bool    `json:"array,omitempty"` // the column is an array of Type
+	Length        int     `json:"length,omitempty"`
+	Precision     int     `json:"precision,omitempty"`
+	Scale         int     `json:"scale,omitempty"`
+	Unsigned      bool    `json:"unsigned,omitempty"`
+	Nullable      bool    `json:"nullable"`
+	Default       *string `json:"default,omitempty"` // the sql expression of the default value, e.g. 'a' or CURRENT_TIMESTAMP
+	AutoIncrement bool    `json:"auto_increment,omitempty"`
+	OnUpdate      string  `json:"on_update,omitempty"`
+	Charset       string  `json:"charset,omitempty"`
+	Collation     string  `json:"collation,omitempty"`
+	Comment       string  `json:"comment,omitempty"`
+}
+
+type IRIndex struct {
+	Name    string    `json:"name,omitempty"`
+	Kind    IndexKind `json:"kind"` // PRIMARY, UNIQUE, INDEX, FULLTEXT or SPATIAL
+	Columns []string  `json:"columns"`
+}
+
+type IRForeignKey struct {
+	Name       string   `json:"name,omitempty"`
+	Columns    []string `json:"columns"`
+	RefTable   string   `json:"ref_table"`
+	RefColumns []string `json:"ref_columns,omitempty"` // omitted when the primary key of RefTable is referenced
+	OnDelete   string   `json:"on_delete,omitempty"`
+	OnUpdate   string   `json:"on_update,omitempty"`
+}
+
+type IRRelation struct {
+	Kind           RelationKind `json:"kind"` // BELONGS_TO, HAS_ONE, HAS_MANY or MANY_TO_MANY
+	Table          string       `json:"table"`
+	Columns        []string     `json:"columns"`
+	RefTable       string       `json:"ref_table"`
+	RefColumns     []string     `json:"ref_columns"`
+	JoinTable      string       `json:"join_table,omitempty"`
+	JoinColumns    []string     `json:"join_columns,omitempty"`
+	JoinRefColumns []string     `json:"join_ref_columns,omitempty"`
+}
+
+// NewIR returns the IR of the tables of the schema, whose column types are of the dialect
+func NewIR(schema *Schema, dialect Dialect) *IR {
+	ir := &IR{Version: IRVersion, Dialect: dialect, Tables: []*IRTable{}}
+	for _, table := range schema.Tables {
+		ir.Tables = append(ir.Tables, newIRTable(table))
+	}
+	for _, relation := range buildRelations(schema.Tables) {
+		ir.Relations = append(ir.Relations, &IRRelation{
+			Kind:           relation.Kind,
+			Table:          relation.Table,
+			Columns:        relation.Columns,
+			RefTable:       relation.RefTable,
+			RefColumns:     relation.RefColumns,
+			JoinTable:      relation.JoinTable,
+			JoinColumns:    relation.JoinColumns,
+			JoinRefColumns: relation.JoinRefColumns,
+		})
+	}
+	return ir
+}
+
+func newIRTable(table *TableStruct) *IRTable {
+	res := &IRTable{
+		Name:    table.TableName,
+		Comment: table.Comment,
+		Columns: []*IRColumn{},
+		Source:  table.SourceFile,
+	}
+	for _, field := range table.Fields {
+		res.Columns = append(res.Columns, &IRColumn{
+			Name:          field.FieldName,
+			Type:          field.FieldType,
+			Array:         field.Array,
+			Length:        field.Length,
+			Precision:     field.Precision,
+			Scale:         field.Scale,
+			Unsigned:      field.Unsigned,
+			Nullable:      field.Nullable,
+			Default:       field.Default,
+			AutoIncrement: field.AutoIncrement,
+			OnUpdate:      field.OnUpdate,
+			Charset:       field.Charset,
+			Collation:     field.Collation,
+			Comment:       field.FieldComment,
+		})
+	}
+	if table.PrimaryKey != nil {
+		res.PrimaryKey = &IRIndex{Name: table.PrimaryKey.Name, Kind: table.PrimaryKey.Kind, Columns: table.PrimaryKey.Columns}
+	}
+	for _, index := range table.Indexes {
+		res.Indexes = append(res.Indexes, &IRIndex{Name: index.Name, Kind: index.Kind, Columns: index.Columns})
+	}
+	for _, foreignKey := range table.ForeignKeys {
+		res.ForeignKeys = append(res.ForeignKeys, &IRForeignKey{
+			Name:       foreignKey.Name,
+			Columns:    foreignKey.Columns,
+			RefTable:   foreignKey.RefTable,
+			RefColumns: foreignKey.RefColumns,
+			OnDelete:   foreignKey.OnDelete,
+			OnUpdate:   foreignKey.OnUpdate,
+		})
+	}
+	return res
+}
+
+// Write writes the IR as indented JSON
+func (ir *IR) Write(w io.Writer) error {
+	b, err := json.MarshalIndent(ir, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// ReadIR reads the IR written as JSON, the version should be IRVersion
+func ReadIR(r io.Reader) (*IR, error) {
+	ir := &IR{}
+	if err := json.NewDecoder(r).Decode(ir); err != nil {
+		return nil, fmt.Errorf("invalid schema IR, err: %v", err)
+	}
+	if ir.Version != IRVersion {
+		return nil, fmt.Errorf("unsupported schema IR version %d, expected %d", ir.Version, IRVersion)
+	}
+	if ir.Dialect != "" && !ir.Dialect.IsAllowed() {
+		return nil, fmt.Errorf("dialect of schema IR should be one of %v", AllowedDialect)
+	}
+	return ir, nil
+}
+
+// Schema returns the schema of the tables of the IR, the relations are derived from the foreign keys again
+func (ir *IR) Schema() (*Schema, error) {
+	schema := &Schema{}
+	for i, t := range ir.Tables {
+		if t == nil || t.Name == "" {
+			return nil, fmt.Errorf("table %d has no name", i)
+		}
+		if schema.GetTable(t.Name) != nil {
+			return nil, fmt.Errorf("duplicate table %s", t.Name)
+		}
+		table, err := t.toTableStruct()
+		if err != nil {
+			return nil, fmt.Errorf("table %s: %v", t.Name, err)
+		}
+		schema.Tables = append(schema.Tables, table)
+	}
+	return schema, nil
+}
+
+func (t *IRTable) toTableStruct() (*TableStruct, error) {
+	table := &TableStruct{
+		TableName:  t.Name,
+		Comment:    t.Comment,
+		SourceFile: t.Source,
+	}
+	for i, column := range t.Columns {
+		// the type is empty when the column is declared without one, e.g. a in CREATE TABLE t (a, b int) of sqlite
+		if column == nil || column.Name == "" {
+			return nil, fmt.Errorf("column %d should have a name", i)
+		}
+		table.Fields = append(table.Fields, &FieldInfo{
+			FieldName:     column.Name,
+			FieldType:     column.Type,
+			FieldComment:  column.Comment,
+			Array:         column.Array,
+			Nullable:      column.Nullable,
+			Default:       column.Default,
+			AutoIncrement: column.AutoIncrement,
+			Unsigned:      column.Unsigned,
+			Length:        column.Length,
+			Precision:     column.Precision,
+			Scale:         column.Scale,
+			Charset:       column.Charset,
+			Collation:     column.Collation,
+			OnUpdate:      column.OnUpdate,
+		})
+	}
+	if t.PrimaryKey != nil {
+		if t.PrimaryKey.Kind != IndexPrimary {
+			return nil, fmt.Errorf("the kind of primary key should be %s", IndexPrimary)
+		}
+		table.PrimaryKey = &IndexInfo{Name: t.PrimaryKey.Name, Kind: IndexPrimary, Columns: t.PrimaryKey.Columns}
+	}
+	for i, index := range t.Indexes {
+		if index == nil {
+			return nil, fmt.Errorf("index %d is null", i)
+		}
+		switch index.Kind {
+		case IndexUnique, IndexNormal, IndexFulltext, IndexSpatial:
+		default:
+			return nil, fmt.Errorf("the kind of index %s should be one of %v", index.Name,
+				[]IndexKind{IndexUnique, IndexNormal, IndexFulltext, IndexSpatial})
+		}
+		table.Indexes = append(table.Indexes, &IndexInfo{Name: index.Name, Kind: index.Kind, Columns: index.Columns})
+	}
+	for i, foreignKey := range t.ForeignKeys {
+		if foreignKey == nil {
+			return nil, fmt.Errorf("foreign key %d is null", i)
+		}
+		if foreignKey.RefTable == "" {
+			return nil, fmt.Errorf("foreign key %s has no referenced table", foreignKey.Name)
+		}
+		table.ForeignKeys = append(table.ForeignKeys, &ForeignKeyInfo{
+			Name:       foreignKey.Name,
+			Columns:    foreignKey.Columns,
+			RefTable:   foreignKey.RefTable,
+			RefColumns: foreignKey.RefColumns,
+			OnDelete:   foreignKey.OnDelete,
+			OnUpdate:   foreignKey.OnUpdate,
+		})
+	}
+	return table, nil
+}
+
+// isIRFile reports whether the file is read as a schema IR, i.e. a .json file
+func isIRFile(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
+
+// readIRFile reads the tables of the schema IR file
+func readIRFile(path string, opts Options) (*sqlSource, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	ir, err := ReadIR(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	schema, err := ir.Schema()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &sqlSource{Path: path, Tables: schema.Tables}, nil
+}
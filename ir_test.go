@@ -0,0 +1,143 @@
+package sqlconverter
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIRRoundTrip(t *testing.T) {
+	parser := &CreateTableSQLParser{
+		SqlFile: "./testdata/mysqldump.sql",
+	}
+	parser.SetDefault()
+	if err := parser.load(); err != nil {
+		t.Fatal(err)
+	}
+	if err := parser.parseSQL(); err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := NewIR(&parser.schema, parser.Dialect).Write(&b); err != nil {
+		t.Fatal(err)
+	}
+	ir, err := ReadIR(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, IRVersion, ir.Version)
+	assert.Equal(t, MySQL, ir.Dialect)
+	assert.Len(t, ir.Relations, 2)
+
+	schema, err := ir.Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, parser.schema.Tables, schema.Tables)
+
+	// a column without a type
+	parser = &CreateTableSQLParser{
+		Sqls:    []string{"CREATE TABLE t (a, b INT NOT NULL)"},
+		Dialect: SQLite,
+	}
+	parser.SetDefault()
+	if err := parser.load(); err != nil {
+		t.Fatal(err)
+	}
+	if err := parser.parseSQL(); err != nil {
+		t.Fatal(err)
+	}
+	b.Reset()
+	if err := NewIR(&parser.schema, parser.Dialect).Write(&b); err != nil {
+		t.Fatal(err)
+	}
+	if ir, err = ReadIR(&b); err != nil {
+		t.Fatal(err)
+	}
+	if schema, err = ir.Schema(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "", schema.Tables[0].Fields[0].FieldType)
+	assert.Equal(t, parser.schema.Tables, schema.Tables)
+}
+
+func TestReadIRError(t *testing.T) {
+	inputs := []string{
+		`{"tables": []}`,
+		`{"version": 2, "tables": []}`,
+		`{"version": 1, "dialect": "ORACLE", "tables": []}`,
+		`{"version": 1, "tables": [`,
+	}
+	for i, input := range inputs {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			_, err := ReadIR(strings.NewReader(input))
+			assert.Error(t, err)
+		})
+	}
+
+	schemas := []string{
+		`{"version": 1, "tables": [{"columns": []}]}`,
+		`{"version": 1, "tables": [{"name": "t", "columns": [{"type": "int"}]}]}`,
+		`{"version": 1, "tables": [{"name": "t", "columns": []}, {"name": "t", "columns": []}]}`,
+		`{"version": 1, "tables": [{"name": "t", "columns": [], "primary_key": {"kind": "UNIQUE", "columns": ["id"]}}]}`,
+		`{"version": 1, "tables": [{"name": "t", "columns": [], "indexes": [{"kind": "KEY", "columns": ["id"]}]}]}`,
+		`{"version": 1, "tables": [{"name": "t", "columns": [], "indexes": [null]}]}`,
+		`{"version": 1, "tables": [{"name": "t", "columns": [], "foreign_keys": [{"columns": ["a"]}]}]}`,
+	}
+	for i, input := range schemas {
+		t.Run(fmt.Sprintf("Schema %d", i), func(t *testing.T) {
+			ir, err := ReadIR(strings.NewReader(input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = ir.Schema()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestInspect(t *testing.T) {
+	var out, info bytes.Buffer
+	stdout, stderr = &out, &info
+	defer func() {
+		stdout, stderr = os.Stdout, os.Stderr
+	}()
+
+	sqls := []string{
+		"CREATE TABLE users (id serial PRIMARY KEY, name text NOT NULL, tags text[])",
+		"CREATE TABLE orders (id bigserial PRIMARY KEY, user_id integer REFERENCES users (id), created_at timestamptz)",
+	}
+	parser := &CreateTableSQLParser{Sqls: sqls, Dialect: PostgreSQL}
+	if err := parser.Inspect(); err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, strings.HasPrefix(out.String(), "{\n  \"version\": 1,\n  \"dialect\": \"POSTGRESQL\","))
+
+	// the IR generates the same code as the statements, and its dialect is the default one
+	path := filepath.Join(t.TempDir(), "schema.json")
+	if err := ioutil.WriteFile(path, out.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	parser = &CreateTableSQLParser{Sqls: sqls, Dialect: PostgreSQL, OutputFile: StdoutPath, PackageName: "models"}
+	if err := parser.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	expected := out.String()
+
+	out.Reset()
+	parser = &CreateTableSQLParser{SqlFile: path, OutputFile: StdoutPath, PackageName: "models"}
+	if err := parser.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, PostgreSQL, parser.Dialect)
+	assert.Equal(t, expected, out.String())
+	assert.Equal(t, fmt.Sprintf("Table: users, from: %s\nTable: orders, from: %s\n", path, path), info.String())
+}
@@ -0,0 +1,436 @@
+package sqlconverter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type TokenKind int32
+
+const (
+	TokenEOF TokenKind = iota
+	TokenIdent
+	TokenQuotedIdent
+	TokenString
+	TokenNumber
+	TokenBitString // a bit or hex literal such as b'01', x'FF', 0b01 or 0xFF, the value is the literal as it is written
+	TokenPunct
+	TokenComment
+)
+
+func (k TokenKind) String() string {
+	switch k {
+	case TokenEOF:
+		return "end of input"
+	case TokenIdent:
+		return "identifier"
+	case TokenQuotedIdent:
+		return "quoted identifier"
+	case TokenString:
+		return "string"
+	case TokenNumber:
+		return "number"
+	case TokenBitString:
+		return "bit string"
+	case TokenPunct:
+		return "punctuation"
+	case TokenComment:
+		return "comment"
+	}
+	return ""
+}
+
+type Token struct {
+	Kind  TokenKind
+	Value string // the unquoted and unescaped value
+	Pos   int    // the rune offset of the first character in the source
+	End   int    // the rune offset just after the last character
+	Line  int
+}
+
+// IsKeyword reports whether the token is the unquoted identifier word, compared case-insensitively
+func (t Token) IsKeyword(word string) bool {
+	return t.Kind == TokenIdent && strings.EqualFold(t.Value, word)
+}
+
+// IsAnyKeyword reports whether the token is one of the unquoted identifier words
+func (t Token) IsAnyKeyword(words []string) bool {
+	for _, word := range words {
+		if t.IsKeyword(word) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t Token) IsPunct(p string) bool {
+	return t.Kind == TokenPunct && t.Value == p
+}
+
+func (t Token) String() string {
+	if t.Kind == TokenEOF {
+		return t.Kind.String()
+	}
+	return fmt.Sprintf("%s %q", t.Kind, t.Value)
+}
+
+// lexerConfig holds the quoting rules which differ between dialects
+type lexerConfig struct {
+	doubleQuoteIdent bool // "name" is an identifier instead of a string
+	backslashEscape  bool // backslash escapes characters in strings
+	dollarQuote      bool // $tag$ ... $tag$ is a string
+	bracketIdent     bool // [name] is an identifier
+	conditionalCode  bool // the content of /*!NNNNN ... */ is code instead of comment
+	batchSeparator   bool // a line of GO separates statements
+	hashComment      bool // # starts a line comment
+	hashIdent        bool // #name and ##name are identifiers of temporary tables
+}
+
+type Lexer struct {
+	src           []rune
+	pos           int
+	line          int
+	cfg           lexerConfig
+	keepComments  bool
+	inConditional bool // inside /*! ... */
+}
+
+func newLexer(src string, cfg lexerConfig) *Lexer {
+	return &Lexer{
+		src:  []rune(src),
+		line: 1,
+		cfg:  cfg,
+	}
+}
+
+func (l *Lexer) peekRune(offset int) rune {
+	if l.pos+offset >= len(l.src) {
+		return 0
+	}
+	return l.src[l.pos+offset]
+}
+
+func (l *Lexer) advance() rune {
+	r := l.src[l.pos]
+	l.pos++
+	if r == '\n' {
+		l.line++
+	}
+	return r
+}
+
+func (l *Lexer) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %d: %s", l.line, fmt.Sprintf(format, args...))
+}
+
+// Next returns the next token of the source, comments are skipped unless keepComments is set
+func (l *Lexer) Next() (Token, error) {
+	for {
+		tok, err := l.scan()
+		if err != nil {
+			return tok, err
+		}
+		if tok.Kind == TokenComment && !l.keepComments {
+			continue
+		}
+		return tok, nil
+	}
+}
+
+// skipSpace skips the white spaces and the end of conditional comments
+func (l *Lexer) skipSpace() {
+	for l.pos < len(l.src) {
+		switch {
+		case unicode.IsSpace(l.src[l.pos]):
+			l.advance()
+		case l.inConditional && l.src[l.pos] == '*' && l.peekRune(1) == '/':
+			l.advance()
+			l.advance()
+			l.inConditional = false
+		default:
+			return
+		}
+	}
+}
+
+func (l *Lexer) scan() (Token, error) {
+	l.skipSpace()
+	// the content of conditional comments such as /*!40101 SET NAMES utf8 */ is lexed as code
+	for l.cfg.conditionalCode && !l.inConditional && l.hasPrefix("/*!") {
+		l.pos += 3
+		for l.pos < len(l.src) && unicode.IsDigit(l.src[l.pos]) {
+			l.advance()
+		}
+		l.inConditional = true
+		l.skipSpace()
+	}
+	tok := Token{Pos: l.pos, Line: l.line}
+	if l.pos >= len(l.src) {
+		tok.End = l.pos
+		return tok, nil
+	}
+
+	var err error
+	r := l.src[l.pos]
+	switch {
+	case r == '-' && l.peekRune(1) == '-', r == '#' && l.cfg.hashComment:
+		tok.Kind, tok.Value = TokenComment, l.scanLineComment()
+	case r == '#' && l.cfg.hashIdent:
+		tok.Kind, tok.Value = TokenIdent, l.scanHashIdent()
+	case r == '/' && l.peekRune(1) == '*':
+		tok.Kind = TokenComment
+		tok.Value, err = l.scanBlockComment()
+	case r == '`':
+		tok.Kind = TokenQuotedIdent
+		tok.Value, err = l.scanQuoted('`', false)
+	case r == '"' && l.cfg.doubleQuoteIdent:
+		tok.Kind = TokenQuotedIdent
+		tok.Value, err = l.scanQuoted('"', false)
+	case r == '"':
+		tok.Kind = TokenString
+		tok.Value, err = l.scanQuoted('"', l.cfg.backslashEscape)
+	case (r == 'N' || r == 'n') && l.peekRune(1) == '\'':
+		// the national character string N'...'
+		l.advance()
+		tok.Kind = TokenString
+		tok.Value, err = l.scanQuoted('\'', l.cfg.backslashEscape)
+	case r == '\'':
+		tok.Kind = TokenString
+		tok.Value, err = l.scanQuoted('\'', l.cfg.backslashEscape)
+	case strings.ContainsRune("bBxX", r) && l.peekRune(1) == '\'', r == '0' && strings.ContainsRune("bBxX", l.peekRune(1)):
+		tok.Kind = TokenBitString
+		tok.Value, err = l.scanBitString()
+	case r == '[' && l.cfg.bracketIdent:
+		tok.Kind = TokenQuotedIdent
+		tok.Value, err = l.scanBracketQuoted()
+	case r == '$' && l.cfg.dollarQuote && l.dollarTag() != "":
+		tok.Kind = TokenString
+		tok.Value, err = l.scanDollarQuoted()
+	case unicode.IsDigit(r) || (r == '.' && unicode.IsDigit(l.peekRune(1))):
+		tok.Kind, tok.Value = TokenNumber, l.scanNumber()
+	case isIdentStart(r):
+		tok.Kind, tok.Value = TokenIdent, l.scanIdent()
+	default:
+		tok.Kind, tok.Value = TokenPunct, string(l.advance())
+	}
+	tok.End = l.pos
+	return tok, err
+}
+
+// hasPrefix reports whether the source at the current position starts with prefix, compared case-insensitively
+func (l *Lexer) hasPrefix(prefix string) bool {
+	rs := []rune(prefix)
+	if l.pos+len(rs) > len(l.src) {
+		return false
+	}
+	return strings.EqualFold(string(l.src[l.pos:l.pos+len(rs)]), prefix)
+}
+
+// restOfLine consumes and returns the source until the end of the current line
+func (l *Lexer) restOfLine() string {
+	start := l.pos
+	for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+		l.advance()
+	}
+	return string(l.src[start:l.pos])
+}
+
+func (l *Lexer) scanLineComment() string {
+	return l.restOfLine()
+}
+
+func (l *Lexer) scanBlockComment() (string, error) {
+	start, line := l.pos, l.line
+	l.advance()
+	l.advance()
+	for l.pos < len(l.src) {
+		if l.src[l.pos] == '*' && l.peekRune(1) == '/' {
+			l.advance()
+			l.advance()
+			return string(l.src[start:l.pos]), nil
+		}
+		l.advance()
+	}
+	return "", fmt.Errorf("line %d: unterminated block comment", line)
+}
+
+// scanQuoted reads a string or quoted identifier closed by quote, the quote itself can be
+// escaped by doubling it, and by backslash when backslash is set
+func (l *Lexer) scanQuoted(quote rune, backslash bool) (string, error) {
+	line := l.line
+	l.advance()
+
+	var value []rune
+	for l.pos < len(l.src) {
+		r := l.advance()
+		switch {
+		case r == quote && l.peekRune(0) == quote:
+			l.advance()
+			value = append(value, quote)
+		case r == quote:
+			return string(value), nil
+		case r == '\\' && backslash && l.pos < len(l.src):
+			value = append(value, unescape(l.advance()))
+		default:
+			value = append(value, r)
+		}
+	}
+	return "", fmt.Errorf("line %d: unterminated %c quote", line, quote)
+}
+
+// scanBracketQuoted reads an identifier such as [name], "]]" escapes "]"
+func (l *Lexer) scanBracketQuoted() (string, error) {
+	line := l.line
+	l.advance()
+
+	var value []rune
+	for l.pos < len(l.src) {
+		r := l.advance()
+		switch {
+		case r == ']' && l.peekRune(0) == ']':
+			l.advance()
+			value = append(value, r)
+		case r == ']':
+			return string(value), nil
+		default:
+			value = append(value, r)
+		}
+	}
+	return "", fmt.Errorf("line %d: unterminated [ quote", line)
+}
+
+// dollarTag returns the opening tag such as $$ or $body$ at the current position, empty if there is none
+func (l *Lexer) dollarTag() string {
+	for i := l.pos + 1; i < len(l.src); i++ {
+		if l.src[i] == '$' {
+			return string(l.src[l.pos : i+1])
+		}
+		if !isIdentPart(l.src[i]) || l.src[i] == '$' {
+			return ""
+		}
+	}
+	return ""
+}
+
+func (l *Lexer) scanDollarQuoted() (string, error) {
+	line := l.line
+	tag := []rune(l.dollarTag())
+	for range tag {
+		l.advance()
+	}
+	start := l.pos
+	for l.pos < len(l.src) {
+		if l.src[l.pos] == '$' && l.pos+len(tag) <= len(l.src) && string(l.src[l.pos:l.pos+len(tag)]) == string(tag) {
+			value := string(l.src[start:l.pos])
+			for range tag {
+				l.advance()
+			}
+			return value, nil
+		}
+		l.advance()
+	}
+	return "", fmt.Errorf("line %d: unterminated %s quote", line, string(tag))
+}
+
+func unescape(r rune) rune {
+	switch r {
+	case 'n':
+		return '\n'
+	case 't':
+		return '\t'
+	case 'r':
+		return '\r'
+	case '0':
+		return 0
+	}
+	return r
+}
+
+// scanBitString reads a bit literal such as b'01' or 0b01, or a hex literal such as x'FF' or 0xFF
+func (l *Lexer) scanBitString() (string, error) {
+	start, line := l.pos, l.line
+	quoted := l.src[l.pos] != '0'
+	if !quoted {
+		l.advance()
+	}
+	digits := "01"
+	if base := unicode.ToLower(l.advance()); base == 'x' {
+		digits = "0123456789abcdefABCDEF"
+	}
+	if quoted {
+		l.advance()
+	}
+	for l.pos < len(l.src) && strings.ContainsRune(digits, l.src[l.pos]) {
+		l.advance()
+	}
+	if quoted {
+		if l.peekRune(0) != '\'' {
+			return "", fmt.Errorf("line %d: invalid bit string %s", line, string(l.src[start:l.pos]))
+		}
+		l.advance()
+	}
+	return string(l.src[start:l.pos]), nil
+}
+
+func (l *Lexer) scanNumber() string {
+	start := l.pos
+	for l.pos < len(l.src) {
+		r := l.src[l.pos]
+		if unicode.IsDigit(r) || r == '.' {
+			l.advance()
+			continue
+		}
+		if (r == 'e' || r == 'E') && (unicode.IsDigit(l.peekRune(1)) ||
+			((l.peekRune(1) == '-' || l.peekRune(1) == '+') && unicode.IsDigit(l.peekRune(2)))) {
+			l.advance()
+			l.advance()
+			continue
+		}
+		break
+	}
+	return string(l.src[start:l.pos])
+}
+
+func (l *Lexer) scanIdent() string {
+	start := l.pos
+	for l.pos < len(l.src) && isIdentPart(l.src[l.pos]) {
+		l.advance()
+	}
+	return string(l.src[start:l.pos])
+}
+
+// scanHashIdent scans the name of a temporary table of SQL Server, e.g. #orders or ##orders
+func (l *Lexer) scanHashIdent() string {
+	start := l.pos
+	for l.pos < len(l.src) && l.src[l.pos] == '#' {
+		l.advance()
+	}
+	l.scanIdent()
+	return string(l.src[start:l.pos])
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// tokenize splits the sql into tokens without comments, the last token is always TokenEOF
+func tokenize(sql string, cfg lexerConfig) ([]Token, error) {
+	lexer := newLexer(sql, cfg)
+
+	var tokens []Token
+	for {
+		tok, err := lexer.Next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, tok)
+		if tok.Kind == TokenEOF {
+			return tokens, nil
+		}
+	}
+}
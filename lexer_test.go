@@ -0,0 +1,152 @@
+package sqlconverter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	inputs := []string{
+		"CREATE TABLE `v_test` (id int(11))",
+		"'it''s' \"a\\\"b\" 'a\\nb'",
+		"1 3.14 1e-3 0xFF -- comment\n /* block */ # another\n x",
+		"`a``b` ,;.",
+		"b'0' B'101' x'FF' X'' 0b1 0x1F b c",
+	}
+	expecteds := [][]Token{
+		{
+			{Kind: TokenIdent, Value: "CREATE"},
+			{Kind: TokenIdent, Value: "TABLE"},
+			{Kind: TokenQuotedIdent, Value: "v_test"},
+			{Kind: TokenPunct, Value: "("},
+			{Kind: TokenIdent, Value: "id"},
+			{Kind: TokenIdent, Value: "int"},
+			{Kind: TokenPunct, Value: "("},
+			{Kind: TokenNumber, Value: "11"},
+			{Kind: TokenPunct, Value: ")"},
+			{Kind: TokenPunct, Value: ")"},
+			{Kind: TokenEOF},
+		},
+		{
+			{Kind: TokenString, Value: "it's"},
+			{Kind: TokenString, Value: "a\"b"},
+			{Kind: TokenString, Value: "a\nb"},
+			{Kind: TokenEOF},
+		},
+		{
+			{Kind: TokenNumber, Value: "1"},
+			{Kind: TokenNumber, Value: "3.14"},
+			{Kind: TokenNumber, Value: "1e-3"},
+			{Kind: TokenBitString, Value: "0xFF"},
+			{Kind: TokenIdent, Value: "x"},
+			{Kind: TokenEOF},
+		},
+		{
+			{Kind: TokenQuotedIdent, Value: "a`b"},
+			{Kind: TokenPunct, Value: ","},
+			{Kind: TokenPunct, Value: ";"},
+			{Kind: TokenPunct, Value: "."},
+			{Kind: TokenEOF},
+		},
+		{
+			{Kind: TokenBitString, Value: "b'0'"},
+			{Kind: TokenBitString, Value: "B'101'"},
+			{Kind: TokenBitString, Value: "x'FF'"},
+			{Kind: TokenBitString, Value: "X''"},
+			{Kind: TokenBitString, Value: "0b1"},
+			{Kind: TokenBitString, Value: "0x1F"},
+			{Kind: TokenIdent, Value: "b"},
+			{Kind: TokenIdent, Value: "c"},
+			{Kind: TokenEOF},
+		},
+	}
+
+	for i, input := range inputs {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			tokens, err := tokenize(input, MySQL.lexerConfig())
+			if err != nil {
+				t.Fatal(err)
+			}
+			var actual []Token
+			for _, tok := range tokens {
+				actual = append(actual, Token{Kind: tok.Kind, Value: tok.Value})
+			}
+			assert.Equal(t, expecteds[i], actual)
+		})
+	}
+}
+
+func TestTokenizeHash(t *testing.T) {
+	inputs := map[Dialect]string{
+		MySQL:      "a # comment\n b",
+		PostgreSQL: "a # b",
+		SQLServer:  "#orders ##orders",
+	}
+	expecteds := map[Dialect][]Token{
+		MySQL: {
+			{Kind: TokenIdent, Value: "a"},
+			{Kind: TokenIdent, Value: "b"},
+			{Kind: TokenEOF},
+		},
+		PostgreSQL: {
+			{Kind: TokenIdent, Value: "a"},
+			{Kind: TokenPunct, Value: "#"},
+			{Kind: TokenIdent, Value: "b"},
+			{Kind: TokenEOF},
+		},
+		SQLServer: {
+			{Kind: TokenIdent, Value: "#orders"},
+			{Kind: TokenIdent, Value: "##orders"},
+			{Kind: TokenEOF},
+		},
+	}
+	for dialect, input := range inputs {
+		t.Run(string(dialect), func(t *testing.T) {
+			tokens, err := tokenize(input, dialect.lexerConfig())
+			if err != nil {
+				t.Fatal(err)
+			}
+			var actual []Token
+			for _, tok := range tokens {
+				actual = append(actual, Token{Kind: tok.Kind, Value: tok.Value})
+			}
+			assert.Equal(t, expecteds[dialect], actual)
+		})
+	}
+}
+
+func TestTokenizeError(t *testing.T) {
+	inputs := []string{
+		"'unterminated",
+		"`unterminated",
+		"/* unterminated",
+		"b'012'",
+		"x'FF",
+	}
+	for i, input := range inputs {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			_, err := tokenize(input, MySQL.lexerConfig())
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLexerKeepComments(t *testing.T) {
+	lexer := newLexer("a -- line\n/* block */", MySQL.lexerConfig())
+	lexer.keepComments = true
+
+	var actual []string
+	for {
+		tok, err := lexer.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tok.Kind == TokenEOF {
+			break
+		}
+		actual = append(actual, tok.Value)
+	}
+	assert.Equal(t, []string{"a", "-- line", "/* block */"}, actual)
+}
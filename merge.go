@@ -0,0 +1,368 @@
+package sqlconverter
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// ManualMarker marks a field of a generated struct as written by hand, e.g. `Orders []*Orders // manual`,
+// MERGE keeps the field when the struct is generated again, and it replaces the generated field of the same name
+const ManualMarker = "manual"
+
+// TableMarker marks a struct as generated from a table, e.g. `// table: users` above `type Users struct`,
+// MERGE replaces the struct generated from the same table whatever its name
+const TableMarker = "table"
+
+// edit replaces src[start:end] with text
+type edit struct {
+	start, end int
+	text       string
+}
+
+// mergeGoFile replaces the structs of the existing file with the generated structs of the same tables, the tables
+// of the structs are read from their TableMarker comments or their TableName methods, and the structs are renamed
+// when their names changed. The generated structs without a table replace the existing ones of the same name,
+// and the generated structs which are not declared yet are appended, an error is returned when the name of a generated
+// struct is taken by a type of another table or by a type which is not generated. The other declarations and comments of the file
+// and the fields marked by ManualMarker are kept, and the imports of the known packages are added or removed
+// as the merged file uses them
+func mergeGoFile(existing, generated []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	dst, err := parser.ParseFile(fset, "existing.go", existing, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parse existing file failed, err: %v", err)
+	}
+	src, err := parser.ParseFile(fset, "generated.go", generated, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parse generated file failed, err: %v", err)
+	}
+	offset := func(pos token.Pos) int {
+		return fset.Position(pos).Offset
+	}
+
+	dstTables, srcTables := structTables(dst), structTables(src)
+	structs := make(map[string]*ast.StructType)
+	byTable := make(map[string]string)
+	for _, ts := range typeSpecs(src) {
+		if st, ok := ts.Type.(*ast.StructType); ok {
+			structs[ts.Name.Name] = st
+			if table := srcTables[ts.Name.Name]; table != "" {
+				byTable[table] = ts.Name.Name
+			}
+		}
+	}
+
+	var edits []edit
+	// renames maps the existing structs to the names of their generated structs, merged keeps the generated
+	// structs which replace existing ones
+	renames := make(map[string]string)
+	merged := make(map[string]bool)
+	for _, ts := range typeSpecs(dst) {
+		name := ts.Name.Name
+		old, ok := ts.Type.(*ast.StructType)
+		if !ok {
+			continue
+		}
+		var (
+			generatedName string
+			matched       bool
+		)
+		if table := dstTables[name]; table != "" {
+			generatedName, matched = byTable[table]
+		}
+		if _, exist := structs[name]; !matched && exist && srcTables[name] == "" {
+			generatedName, matched = name, true
+		}
+		if !matched {
+			continue
+		}
+		if generatedName != name {
+			renames[name] = generatedName
+		}
+		merged[generatedName] = true
+		generatedStruct := structs[generatedName]
+
+		var fields []string
+		manuals := make(map[string]bool)
+		for _, field := range old.Fields.List {
+			if !isManualField(field) {
+				continue
+			}
+			start, end := field.Pos(), field.End()
+			if field.Doc != nil {
+				start = field.Doc.Pos()
+			}
+			if field.Comment != nil {
+				end = field.Comment.End()
+			}
+			fields = append(fields, string(existing[offset(start):offset(end)]))
+			for _, name := range field.Names {
+				manuals[name.Name] = true
+			}
+		}
+		var res []string
+		for _, field := range generatedStruct.Fields.List {
+			if len(field.Names) == 1 && manuals[field.Names[0].Name] {
+				continue
+			}
+			res = append(res, string(generated[offset(field.Pos()):offset(field.End())]))
+		}
+		edits = append(edits, edit{
+			start: offset(old.Pos()),
+			end:   offset(old.End()),
+			text:  "struct {\n" + strings.Join(append(res, fields...), "\n") + "\n}",
+		})
+	}
+
+	// the generated declarations of new tables, the existing ones except the structs are left as they are
+	declared := make(map[string]bool)
+	for _, decl := range dst.Decls {
+		for _, key := range declKeys(decl, existing, offset) {
+			name, method := key, ""
+			if i := strings.Index(key, "."); i >= 0 {
+				name, method = key[:i], key[i:]
+			}
+			if renamed, exist := renames[name]; exist {
+				key = renamed + method
+			}
+			declared[key] = true
+		}
+	}
+	for _, ts := range typeSpecs(src) {
+		name := ts.Name.Name
+		if table := srcTables[name]; table != "" && declared[name] && !merged[name] {
+			return nil, fmt.Errorf("type %s is not generated from table %s, mark it by // %s: %s to merge it",
+				name, table, TableMarker, table)
+		}
+	}
+	var appended []string
+	for _, decl := range src.Decls {
+		keys := declKeys(decl, generated, offset)
+		exist := len(keys) == 0
+		for _, key := range keys {
+			exist = exist || declared[key] || merged[key]
+		}
+		if exist {
+			continue
+		}
+		start := decl.Pos()
+		if doc := declDoc(decl); doc != nil {
+			start = doc.Pos()
+		}
+		appended = append(appended, string(generated[offset(start):offset(decl.End())]))
+	}
+	res := applyEdits(existing, edits)
+	if len(appended) > 0 {
+		res = strings.TrimRight(res, "\n") + "\n\n" + strings.Join(appended, "\n\n") + "\n"
+	}
+
+	b, err := fixFile(res, renames)
+	if err != nil {
+		return nil, err
+	}
+	if b, err = format.Source(b); err != nil {
+		return nil, fmt.Errorf("merged code is invalid, err: %v", err)
+	}
+	return b, nil
+}
+
+// typeSpecs returns the type specs of the file in order
+func typeSpecs(f *ast.File) []*ast.TypeSpec {
+	var res []*ast.TypeSpec
+	for _, decl := range f.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.TYPE {
+			for _, spec := range gen.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok {
+					res = append(res, ts)
+				}
+			}
+		}
+	}
+	return res
+}
+
+// structTables maps the types of the file to their tables, which are given by the TableMarker comments of the types
+// or returned by their TableName methods
+func structTables(f *ast.File) map[string]string {
+	res := make(map[string]string)
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				for _, group := range []*ast.CommentGroup{ts.Doc, decl.Doc} {
+					if table := markedTable(group); table != "" && (group == ts.Doc || len(decl.Specs) == 1) {
+						res[ts.Name.Name] = table
+						break
+					}
+				}
+			}
+		case *ast.FuncDecl:
+			if decl.Name.Name != "TableName" || decl.Recv == nil || len(decl.Recv.List) != 1 || decl.Body == nil ||
+				len(decl.Body.List) != 1 {
+				continue
+			}
+			recv := decl.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+			ret, ok := decl.Body.List[0].(*ast.ReturnStmt)
+			name, isIdent := recv.(*ast.Ident)
+			if !ok || !isIdent || len(ret.Results) != 1 {
+				continue
+			}
+			if lit, ok := ret.Results[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				if table, err := strconv.Unquote(lit.Value); err == nil && res[name.Name] == "" {
+					res[name.Name] = table
+				}
+			}
+		}
+	}
+	return res
+}
+
+// markedTable returns the table of a TableMarker comment, e.g. users of `// table: users`
+func markedTable(group *ast.CommentGroup) string {
+	if group == nil {
+		return ""
+	}
+	for _, line := range strings.Split(group.Text(), "\n") {
+		if rest := strings.TrimPrefix(strings.TrimSpace(line), TableMarker+":"); rest != strings.TrimSpace(line) {
+			return strings.TrimSpace(rest)
+		}
+	}
+	return ""
+}
+
+// declKeys returns the names declared by the declaration, the methods are named by their receiver types,
+// and the blank declarations by their source. The imports declare nothing
+func declKeys(decl ast.Decl, src []byte, offset func(token.Pos) int) []string {
+	var res []string
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		name := decl.Name.Name
+		if decl.Recv != nil && len(decl.Recv.List) > 0 {
+			recv := decl.Recv.List[0].Type
+			name = string(src[offset(recv.Pos()):offset(recv.End())]) + "." + name
+		}
+		res = append(res, strings.TrimPrefix(name, "*"))
+	case *ast.GenDecl:
+		for _, spec := range decl.Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				res = append(res, spec.Name.Name)
+			case *ast.ValueSpec:
+				for _, name := range spec.Names {
+					key := name.Name
+					if key == "_" {
+						key = string(src[offset(spec.Pos()):offset(spec.End())])
+					}
+					res = append(res, key)
+				}
+			}
+		}
+	}
+	return res
+}
+
+func declDoc(decl ast.Decl) *ast.CommentGroup {
+	switch decl := decl.(type) {
+	case *ast.FuncDecl:
+		return decl.Doc
+	case *ast.GenDecl:
+		return decl.Doc
+	}
+	return nil
+}
+
+// isManualField reports whether the doc or the line comment of the field starts with ManualMarker
+func isManualField(field *ast.Field) bool {
+	for _, group := range []*ast.CommentGroup{field.Doc, field.Comment} {
+		if group == nil {
+			continue
+		}
+		for _, line := range strings.Split(group.Text(), "\n") {
+			line = strings.TrimSpace(line)
+			if line == ManualMarker || strings.HasPrefix(line, ManualMarker+":") || strings.HasPrefix(line, ManualMarker+" ") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func applyEdits(src []byte, edits []edit) string {
+	sort.Slice(edits, func(i, j int) bool {
+		return edits[i].start < edits[j].start
+	})
+	var b strings.Builder
+	last := 0
+	for _, e := range edits {
+		b.Write(src[last:e.start])
+		b.WriteString(e.text)
+		last = e.end
+	}
+	b.Write(src[last:])
+	return b.String()
+}
+
+// fixFile renames the types of the file and the identifiers which refer to them, the imports of the packages
+// of typeImports are added when the file uses them and removed when it does not, the other imports are kept
+func fixFile(src string, renames map[string]string) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "merged.go", src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("merged code is invalid, err: %v", err)
+	}
+	types := make(map[interface{}]bool)
+	for _, ts := range typeSpecs(f) {
+		types[ts] = true
+	}
+	// the package qualifiers are the identifiers which are not declared in the file
+	used := make(map[string]bool)
+	ast.Inspect(f, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.SelectorExpr:
+			if pkg, ok := node.X.(*ast.Ident); ok && pkg.Obj == nil {
+				used[pkg.Name] = true
+			}
+		case *ast.Ident:
+			if renamed, exist := renames[node.Name]; exist && node.Obj != nil && types[node.Obj.Decl] {
+				node.Name = renamed
+			}
+		}
+		return true
+	})
+	changed := false
+	for name, path := range typeImports {
+		if used[name] {
+			changed = astutil.AddImport(fset, f, path) || changed
+		} else {
+			changed = astutil.DeleteImport(fset, f, path) || changed
+		}
+	}
+	// a single import is not parenthesized
+	for _, decl := range f.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && changed && gen.Tok == token.IMPORT && len(gen.Specs) == 1 {
+			gen.Lparen, gen.Rparen = token.NoPos, token.NoPos
+		}
+	}
+
+	var b bytes.Buffer
+	if err := format.Node(&b, fset, f); err != nil {
+		return nil, fmt.Errorf("merged code is invalid, err: %v", err)
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,210 @@
+package sqlconverter
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeGoFile(t *testing.T) {
+	generated := "package models\n\nimport \"database/sql\"\n\n" +
+		"// table: users\ntype Users struct {\n\tID   int64          `db:\"id\"`\n\tName sql.NullString `db:\"name\"`\n}\n\n" +
+		"func (Users) TableName() string { return \"users\" }\n\n" +
+		"// table: orders\ntype Orders struct {\n\tID int64 `db:\"id\"`\n}\n"
+	inputs := []string{
+		// the manual fields, methods, comments and other declarations are kept, the unused import is removed
+		`package dao
+
+import (
+	"context"
+	"time"
+)
+
+// Users is a user of the shop
+type Users struct {
+	ID      int64     ` + "`db:\"id\"`" + `
+	Removed time.Time ` + "`db:\"removed\"`" + `
+	// manual: loaded by the service
+	Orders []*Orders
+	Name   string // manual
+}
+
+type Status int
+
+func (Users) TableName() string { return "users" }
+
+func (u *Users) Load(ctx context.Context) error { return nil }
+`,
+		// the import is added
+		"package dao\n\n// table: orders\ntype Orders struct{ Old int }\n",
+		// the struct of the table is renamed, the variables named by a package do not import it
+		`package dao
+
+// table: users
+type User struct {
+	ID int64
+}
+
+func (u *User) Sync(sql *User) *User {
+	var time User
+	_ = time.ID
+	return sql
+}
+`,
+	}
+	expecteds := []string{
+		`package dao
+
+import "context"
+
+// Users is a user of the shop
+type Users struct {
+	ID int64 ` + "`db:\"id\"`" + `
+	// manual: loaded by the service
+	Orders []*Orders
+	Name   string // manual
+}
+
+type Status int
+
+func (Users) TableName() string { return "users" }
+
+func (u *Users) Load(ctx context.Context) error { return nil }
+
+// table: orders
+type Orders struct {
+	ID int64 ` + "`db:\"id\"`" + `
+}
+`,
+		`package dao
+
+import "database/sql"
+
+// table: orders
+type Orders struct {
+	ID int64 ` + "`db:\"id\"`" + `
+}
+
+// table: users
+type Users struct {
+	ID   int64          ` + "`db:\"id\"`" + `
+	Name sql.NullString ` + "`db:\"name\"`" + `
+}
+
+func (Users) TableName() string { return "users" }
+`,
+		`package dao
+
+import "database/sql"
+
+// table: users
+type Users struct {
+	ID   int64          ` + "`db:\"id\"`" + `
+	Name sql.NullString ` + "`db:\"name\"`" + `
+}
+
+func (u *Users) Sync(sql *Users) *Users {
+	var time Users
+	_ = time.ID
+	return sql
+}
+
+func (Users) TableName() string { return "users" }
+
+// table: orders
+type Orders struct {
+	ID int64 ` + "`db:\"id\"`" + `
+}
+`,
+	}
+	for i, input := range inputs {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			actual, err := mergeGoFile([]byte(input), []byte(generated))
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, expecteds[i], string(actual))
+
+			// merging again changes nothing
+			again, err := mergeGoFile(actual, []byte(generated))
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, string(actual), string(again))
+		})
+	}
+
+	errInputs := []string{
+		"package dao\n\ntype Users struct {",
+		// the types which share the name of a generated struct are not replaced
+		"package dao\n\ntype Users []string\n",
+		"package dao\n\ntype Users struct{ Name string }\n",
+		"package dao\n\n// table: members\ntype Users struct{ Name string }\n",
+	}
+	for i, input := range errInputs {
+		t.Run(fmt.Sprintf("Error %d", i), func(t *testing.T) {
+			_, err := mergeGoFile([]byte(input), []byte(generated))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseMerge(t *testing.T) {
+	var info bytes.Buffer
+	stderr = &info
+	defer func() {
+		stderr = os.Stderr
+	}()
+
+	dir := t.TempDir()
+	parse := func(sqls ...string) error {
+		parser := &CreateTableSQLParser{
+			Sqls:      sqls,
+			TargetDir: dir,
+			Mode:      MERGE,
+		}
+		return parser.Parse()
+	}
+	// the file is created when it does not exist
+	if err := parse("CREATE TABLE users (id int NOT NULL, name varchar(32))"); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, DefaultFileName)
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	method := "\nfunc (u *Users) String() string { return u.Nick }\n"
+	b = bytes.Replace(append(b, method...), []byte("}\n"), []byte("\tNick string `json:\"nick\"` // manual\n}\n"), 1)
+	if err := ioutil.WriteFile(path, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := parse("CREATE TABLE users (id int NOT NULL, name varchar(32) NOT NULL)"); err != nil {
+		t.Fatal(err)
+	}
+	b, err = ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "package "+dirPackageName(dir)+"\n\n// table: users\ntype Users struct {\n"+
+		"\tID   int32  `json:\"id\" db:\"id\"`\n"+
+		"\tName string `json:\"name\" db:\"name\"`\n"+
+		"\tNick string `json:\"nick\"` // manual\n}\n"+method, string(b))
+
+	// only go files are merged
+	parser := &CreateTableSQLParser{
+		Sqls:       []string{"CREATE TABLE users (id int NOT NULL)"},
+		OutputFile: filepath.Join(dir, "users.txt"),
+		Mode:       MERGE,
+	}
+	if err := ioutil.WriteFile(parser.OutputFile, []byte("users"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	assert.Error(t, parser.Parse())
+}
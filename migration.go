@@ -0,0 +1,153 @@
+package sqlconverter
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// migrationFilePattern matches the migration files of golang-migrate, e.g. 000001_create_users.up.sql,
+// and of goose, e.g. 20230101120000_create_users.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.*?)(\.up|\.down)?\.sql$`)
+
+type migration struct {
+	Version uint64
+	Name    string
+	Path    string
+}
+
+// listMigrations returns the up migrations of dir ordered by version, down migrations and files
+// which are not named like migrations are skipped
+func listMigrations(dir string) ([]*migration, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*migration
+	versions := make(map[uint64]string)
+	for _, info := range infos {
+		matches := migrationFilePattern.FindStringSubmatch(info.Name())
+		if info.IsDir() || matches == nil || matches[3] == ".down" {
+			continue
+		}
+		version, err := strconv.ParseUint(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version of migration %s, err: %v", info.Name(), err)
+		}
+		if other, exist := versions[version]; exist {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, other, info.Name())
+		}
+		versions[version] = info.Name()
+		res = append(res, &migration{
+			Version: version,
+			Name:    matches[2],
+			Path:    filepath.Join(dir, info.Name()),
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Version < res[j].Version
+	})
+	return res, nil
+}
+
+// gooseUpStatements returns the statements of the -- +goose Up sections which define or change tables, a block
+// between -- +goose StatementBegin and -- +goose StatementEnd is a single statement. Content without goose
+// annotations is split as it is
+func gooseUpStatements(content string, dialect Dialect) ([]string, error) {
+	var (
+		res          []string
+		annotated    bool
+		up, inBlock  bool
+		chunkStart   int
+		blockLines   []string
+		lines        = strings.Split(content, "\n")
+		sectionLines = make([]string, len(lines))
+	)
+	// flush splits the lines of the up sections read since the last block, the lines before them
+	// are kept empty to keep the line numbers
+	flush := func(end int) error {
+		sqls, err := readCreateSQL([]byte(strings.Join(sectionLines[:end], "\n")), dialect)
+		if err != nil {
+			return err
+		}
+		res = append(res, sqls...)
+		for i := chunkStart; i < end; i++ {
+			sectionLines[i] = ""
+		}
+		chunkStart = end
+		return nil
+	}
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) >= 3 && fields[0] == "--" && fields[1] == "+goose" {
+			switch strings.ToUpper(fields[2]) {
+			case "UP":
+				annotated, up = true, true
+			case "DOWN":
+				annotated, up = true, false
+			case "STATEMENTBEGIN":
+				if up {
+					if err := flush(i); err != nil {
+						return nil, err
+					}
+					inBlock, blockLines = true, nil
+				}
+			case "STATEMENTEND":
+				if up && inBlock {
+					if stmt := blockStatement(strings.Join(blockLines, "\n"), dialect); isDDL(stmt) {
+						res = append(res, stmt)
+					}
+					inBlock, chunkStart = false, i+1
+				}
+			}
+			continue
+		}
+		switch {
+		case !up:
+		case inBlock:
+			blockLines = append(blockLines, line)
+		default:
+			sectionLines[i] = line
+		}
+	}
+	if !annotated {
+		return readCreateSQL([]byte(content), dialect)
+	}
+	if err := flush(len(lines)); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// blockStatement returns the statement of a goose statement block without the comments before it and its delimiter
+func blockStatement(block string, dialect Dialect) string {
+	lexer := newLexer(block, dialect.lexerConfig())
+	tok, err := lexer.Next()
+	if err != nil || tok.Kind == TokenEOF {
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimSpace(string(lexer.src[tok.Pos:])), defaultDelimiter)
+}
+
+// readMigrations reads the statements of the migrations, only the -- +goose Up sections are read
+// from the files of goose
+func readMigrations(migrations []*migration, dialect Dialect) ([]*sqlSource, error) {
+	var res []*sqlSource
+	for _, m := range migrations {
+		content, err := ioutil.ReadFile(m.Path)
+		if err != nil {
+			return nil, err
+		}
+		sqls, err := gooseUpStatements(string(content), dialect)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", m.Path, err)
+		}
+		res = append(res, &sqlSource{Path: m.Path, Sqls: sqls})
+	}
+	return res, nil
+}
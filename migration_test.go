@@ -0,0 +1,88 @@
+package sqlconverter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListMigrations(t *testing.T) {
+	migrations, err := listMigrations("./testdata/migrate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, m := range migrations {
+		names = append(names, fmt.Sprintf("%d_%s", m.Version, m.Name))
+	}
+	assert.Equal(t, []string{"1_create_users", "2_add_name", "10_create_orders"}, names)
+}
+
+func TestGooseUpStatements(t *testing.T) {
+	inputs := []string{
+		"-- +goose Up\nCREATE TABLE a (id int);\n-- +goose Down\nDROP TABLE a;\n",
+		"-- +goose NO TRANSACTION\n-- +goose Up\nCREATE TABLE a (id int);\n",
+		"CREATE TABLE a (id int);\n",
+		"-- +goose Up\nCREATE TABLE a (id int, updated_at timestamptz);\n" +
+			"-- +goose StatementBegin\n-- touches a\nCREATE OR REPLACE FUNCTION touch() RETURNS trigger AS $$\n" +
+			"BEGIN\n    NEW.updated_at = now();\n    RETURN NEW;\nEND;\n$$ LANGUAGE plpgsql;\n-- +goose StatementEnd\n" +
+			"ALTER TABLE a ADD COLUMN name text;\n" +
+			"-- +goose Down\n-- +goose StatementBegin\nDROP FUNCTION touch;\n-- +goose StatementEnd\nDROP TABLE a;\n",
+	}
+	expecteds := [][]string{
+		{"CREATE TABLE a (id int)"},
+		{"CREATE TABLE a (id int)"},
+		{"CREATE TABLE a (id int)"},
+		{
+			"CREATE TABLE a (id int, updated_at timestamptz)",
+			"CREATE OR REPLACE FUNCTION touch() RETURNS trigger AS $$\n" +
+				"BEGIN\n    NEW.updated_at = now();\n    RETURN NEW;\nEND;\n$$ LANGUAGE plpgsql",
+			"ALTER TABLE a ADD COLUMN name text",
+		},
+	}
+	for i, input := range inputs {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			actual, err := gooseUpStatements(input, PostgreSQL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, expecteds[i], actual)
+		})
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	cases := map[string]*CreateTableSQLParser{
+		"Migrate": {SqlFile: "./testdata/migrate"},
+		"Goose":   {SqlFile: "./testdata/goose", Dialect: PostgreSQL},
+	}
+	expected := map[string]map[string][]string{
+		"Migrate": {
+			"users":  {"id", "name", "email"},
+			"orders": {"id", "user_id"},
+		},
+		"Goose": {
+			"members": {"id", "name", "updated_at"},
+		},
+	}
+
+	for name, parser := range cases {
+		t.Run(name, func(t *testing.T) {
+			parser.SetDefault()
+			if err := parser.load(); err != nil {
+				t.Fatal(err)
+			}
+			if err := parser.parseSQL(); err != nil {
+				t.Fatal(err)
+			}
+			actual := make(map[string][]string)
+			for _, table := range parser.schema.Tables {
+				for _, field := range table.Fields {
+					actual[table.TableName] = append(actual[table.TableName], field.FieldName)
+				}
+			}
+			assert.Equal(t, expected[name], actual)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package sqlconverter
+
+import (
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// DefaultPackageName is the package of the generated code when it can not be derived from the directory
+const DefaultPackageName = "main"
+
+// IsPackageName reports whether name can be the package clause of a go file
+func IsPackageName(name string) bool {
+	return token.IsIdentifier(name) && name != "_"
+}
+
+// dirPackageName returns the package of the go files in dir, or the name of dir when it has no go files.
+// DefaultPackageName is returned when neither is a valid package name
+func dirPackageName(dir string) string {
+	if name := goFilesPackageName(dir); name != "" {
+		return name
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return DefaultPackageName
+	}
+	if name := sanitizePackageName(filepath.Base(abs)); name != "" {
+		return name
+	}
+	return DefaultPackageName
+}
+
+// goFilesPackageName returns the package clause of the first go file in dir, the test files are skipped
+func goFilesPackageName(dir string) string {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || filepath.Ext(name) != ".go" || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, name), nil, parser.PackageClauseOnly)
+		if err != nil {
+			continue
+		}
+		if pkg := f.Name.Name; IsPackageName(pkg) && !strings.HasSuffix(pkg, "_test") {
+			return pkg
+		}
+	}
+	return ""
+}
+
+// sanitizePackageName lowers the case of name and drops the characters which are not allowed, e.g. sql-converter
+// becomes sqlconverter, empty is returned when the result is not a valid package name
+func sanitizePackageName(name string) string {
+	var res []rune
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			res = append(res, r)
+		}
+	}
+	if !IsPackageName(string(res)) {
+		return ""
+	}
+	return string(res)
+}
@@ -0,0 +1,85 @@
+package sqlconverter
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizePackageName(t *testing.T) {
+	inputs := []string{"models", "sql-converter", "My_Models", "v2", "001", "type", "-", "模型"}
+	expecteds := []string{"models", "sqlconverter", "my_models", "v2", "", "", "", "模型"}
+	for i, input := range inputs {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			assert.Equal(t, expecteds[i], sanitizePackageName(input))
+		})
+	}
+}
+
+func TestDirPackageName(t *testing.T) {
+	root := t.TempDir()
+	write := func(path, content string) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the package of the existing go files wins over the name of the directory
+	write(filepath.Join(root, "models", "a_test.go"), "package models_test\n")
+	write(filepath.Join(root, "models", "b.go"), "package entity\n\ntype A struct{}\n")
+	// the test files and the files which can not be parsed are skipped
+	write(filepath.Join(root, "user-models", "a.go"), "not go")
+	write(filepath.Join(root, "user-models", "b_test.go"), "package usermodels_test\n")
+	if err := os.MkdirAll(filepath.Join(root, "2023"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	inputs := []string{
+		filepath.Join(root, "models"),
+		filepath.Join(root, "user-models"),
+		filepath.Join(root, "2023"),
+		filepath.Join(root, "unknown", "dao"),
+		".",
+	}
+	expecteds := []string{"entity", "usermodels", DefaultPackageName, "dao", "sqlconverter"}
+	for i, input := range inputs {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			assert.Equal(t, expecteds[i], dirPackageName(input))
+		})
+	}
+}
+
+func TestParsePackageName(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "models")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	parser := &CreateTableSQLParser{
+		Sqls:      []string{"CREATE TABLE users (id int NOT NULL)"},
+		TargetDir: dir,
+	}
+	if err := parser.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, DefaultFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "package models\n\n// table: users\ntype Users struct {\n\tID int32 `json:\"id\" db:\"id\"`\n}\n", string(b))
+
+	// the output file decides the directory
+	output := filepath.Join(t.TempDir(), "dao", "users.go")
+	parser = &CreateTableSQLParser{
+		Sqls:       []string{"CREATE TABLE users (id int NOT NULL)"},
+		TargetDir:  dir,
+		OutputFile: output,
+	}
+	parser.SetDefault()
+	assert.Equal(t, "dao", parser.PackageName)
+}
@@ -0,0 +1,227 @@
+package sqlconverter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type RelationKind string
+
+const (
+	RelationBelongsTo  RelationKind = "BELONGS_TO"
+	RelationHasOne     RelationKind = "HAS_ONE"
+	RelationHasMany    RelationKind = "HAS_MANY"
+	RelationManyToMany RelationKind = "MANY_TO_MANY"
+)
+
+// Relation is an association from Table to RefTable, e.g. orders BELONGS_TO users
+// through orders.user_id = users.id, or users HAS_MANY orders through users.id = orders.user_id
+type Relation struct {
+	Kind       RelationKind
+	Table      string
+	Columns    []string // the columns of Table
+	RefTable   string
+	RefColumns []string // the columns of RefTable
+
+	// the join table of MANY_TO_MANY relations, whose JoinColumns reference Table.Columns
+	// and JoinRefColumns reference RefTable.RefColumns
+	JoinTable      string
+	JoinColumns    []string
+	JoinRefColumns []string
+}
+
+func (r *Relation) String() string {
+	b, _ := json.Marshal(r)
+	return string(b)
+}
+
+// relationsOf returns the relations owned by table
+func relationsOf(relations []*Relation, table string) []*Relation {
+	var res []*Relation
+	for _, relation := range relations {
+		if relation.Table == table {
+			res = append(res, relation)
+		}
+	}
+	return res
+}
+
+// buildRelations derives the relations between tables from their foreign keys, references
+// to tables out of the given ones are ignored
+func buildRelations(tables []*TableStruct) []*Relation {
+	byName := make(map[string]*TableStruct)
+	for _, table := range tables {
+		byName[table.TableName] = table
+	}
+	refColumnsOf := func(fk *ForeignKeyInfo) []string {
+		if len(fk.RefColumns) > 0 {
+			return fk.RefColumns
+		}
+		if ref := byName[fk.RefTable]; ref.PrimaryKey != nil {
+			return ref.PrimaryKey.Columns
+		}
+		return nil
+	}
+
+	var relations []*Relation
+	for _, table := range tables {
+		var foreignKeys []*ForeignKeyInfo
+		for _, fk := range table.ForeignKeys {
+			if _, exist := byName[fk.RefTable]; exist {
+				foreignKeys = append(foreignKeys, fk)
+			}
+		}
+		join := isJoinTable(table, foreignKeys)
+
+		for _, fk := range foreignKeys {
+			refColumns := refColumnsOf(fk)
+			relations = append(relations, &Relation{
+				Kind:       RelationBelongsTo,
+				Table:      table.TableName,
+				Columns:    fk.Columns,
+				RefTable:   fk.RefTable,
+				RefColumns: refColumns,
+			})
+			if join {
+				continue
+			}
+			kind := RelationHasMany
+			if table.isUnique(fk.Columns) {
+				kind = RelationHasOne
+			}
+			relations = append(relations, &Relation{
+				Kind:       kind,
+				Table:      fk.RefTable,
+				Columns:    refColumns,
+				RefTable:   table.TableName,
+				RefColumns: fk.Columns,
+			})
+		}
+
+		if !join {
+			continue
+		}
+		for i, fk := range foreignKeys {
+			other := foreignKeys[1-i]
+			relations = append(relations, &Relation{
+				Kind:           RelationManyToMany,
+				Table:          fk.RefTable,
+				Columns:        refColumnsOf(fk),
+				RefTable:       other.RefTable,
+				RefColumns:     refColumnsOf(other),
+				JoinTable:      table.TableName,
+				JoinColumns:    fk.Columns,
+				JoinRefColumns: other.Columns,
+			})
+		}
+	}
+	return relations
+}
+
+// isJoinTable reports whether the table only links two tables, i.e. it has exactly two foreign
+// keys whose columns together are the primary key or a unique constraint of the table
+func isJoinTable(table *TableStruct, foreignKeys []*ForeignKeyInfo) bool {
+	if len(foreignKeys) != 2 {
+		return false
+	}
+	var columns []string
+	columns = append(columns, foreignKeys[0].Columns...)
+	columns = append(columns, foreignKeys[1].Columns...)
+	return table.isUnique(columns)
+}
+
+// addRelationFields appends the association fields of the relations owned by the tables to their structs, with the gorm
+// tags which join them, e.g. User *Users `gorm:"foreignKey:UserID;references:ID"` of orders BELONGS_TO users
+func (parser *CreateTableSQLParser) addRelationFields() {
+	indexes := make(map[string]int)
+	for i, table := range parser.schema.Tables {
+		indexes[table.TableName] = i
+	}
+	// fieldNames returns the go field names of the columns of the table joined by ","
+	fieldNames := func(table string, columns []string) string {
+		var res []string
+		for _, column := range columns {
+			name := parser.getConvertFunc()(parser.cleanFieldName(column))
+			if i, exist := indexes[table]; exist {
+				for j, field := range parser.schema.Tables[i].Fields {
+					if field.FieldName == column {
+						name = parser.structs[i].Fields[j].FieldName
+					}
+				}
+			}
+			res = append(res, name)
+		}
+		return strings.Join(res, ",")
+	}
+
+	for i, table := range parser.schema.Tables {
+		ss := parser.structs[i]
+		names := make(map[string]bool)
+		for _, field := range ss.Fields {
+			names[field.FieldName] = true
+		}
+		for _, relation := range relationsOf(parser.relations, table.TableName) {
+			j, exist := indexes[relation.RefTable]
+			if !exist {
+				continue
+			}
+			name, typ := parser.structs[j].StructName, "*"+parser.structs[j].StructName
+			var tag string
+			switch relation.Kind {
+			case RelationBelongsTo:
+				// orders.user_id is named User
+				if len(relation.Columns) == 1 {
+					if base := trimIDSuffix(relation.Columns[0]); base != "" {
+						name = parser.getConvertFunc()(parser.cleanFieldName(base))
+					}
+				}
+				tag = fmt.Sprintf("foreignKey:%s;references:%s",
+					fieldNames(relation.Table, relation.Columns), fieldNames(relation.RefTable, relation.RefColumns))
+			case RelationHasOne, RelationHasMany:
+				if relation.Kind == RelationHasMany {
+					name, typ = pluralName(name), "[]"+typ
+				}
+				tag = fmt.Sprintf("foreignKey:%s;references:%s",
+					fieldNames(relation.RefTable, relation.RefColumns), fieldNames(relation.Table, relation.Columns))
+			case RelationManyToMany:
+				name, typ = pluralName(name), "[]"+typ
+				tag = fmt.Sprintf("many2many:%s;foreignKey:%s;joinForeignKey:%s;references:%s;joinReferences:%s",
+					relation.JoinTable, fieldNames(relation.Table, relation.Columns), fieldNames(relation.JoinTable, relation.JoinColumns),
+					fieldNames(relation.RefTable, relation.RefColumns), fieldNames(relation.JoinTable, relation.JoinRefColumns))
+			}
+			// the names are numbered when the table has several relations to the same table
+			unique := name
+			for n := 2; names[unique]; n++ {
+				unique = fmt.Sprintf("%s%d", name, n)
+			}
+			names[unique] = true
+			ss.Fields = append(ss.Fields, &SSField{
+				FieldName: unique,
+				FiledType: typ,
+				Comment:   wrapperBackQuote("gorm:" + strconv.Quote(tag)),
+			})
+		}
+	}
+}
+
+// trimIDSuffix returns the column name without its id suffix, e.g. user for user_id or userId, empty when it has none
+func trimIDSuffix(column string) string {
+	switch n := len(column); {
+	case n > 3 && strings.EqualFold(column[n-3:], "_id"):
+		return column[:n-3]
+	case n > 2 && (strings.HasSuffix(column, "ID") || strings.HasSuffix(column, "Id")) && unicode.IsLower(rune(column[n-3])):
+		return column[:n-2]
+	}
+	return ""
+}
+
+// pluralName returns the plural of the struct name unless it is already plural, e.g. Order becomes Orders
+func pluralName(name string) string {
+	if singular(name) == name {
+		return plural(name)
+	}
+	return name
+}
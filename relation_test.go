@@ -0,0 +1,93 @@
+package sqlconverter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildRelations(t *testing.T) {
+	sqls := []string{
+		"CREATE TABLE users (id BIGINT PRIMARY KEY)",
+		"CREATE TABLE profiles (id BIGINT PRIMARY KEY, user_id BIGINT UNIQUE REFERENCES users (id))",
+		"CREATE TABLE orders (id BIGINT PRIMARY KEY, user_id BIGINT, FOREIGN KEY (user_id) REFERENCES users (id), " +
+			"FOREIGN KEY (shop_id) REFERENCES shops (id))",
+		"CREATE TABLE roles (id BIGINT PRIMARY KEY)",
+		"CREATE TABLE user_roles (user_id BIGINT, role_id BIGINT, PRIMARY KEY (user_id, role_id), " +
+			"FOREIGN KEY (user_id) REFERENCES users, FOREIGN KEY (role_id) REFERENCES roles)",
+	}
+	var tables []*TableStruct
+	for _, sql := range sqls {
+		table, err := extractTableStruct(sql, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tables = append(tables, table)
+	}
+	id := []string{"id"}
+	userID := []string{"user_id"}
+	expected := []*Relation{
+		{Kind: RelationBelongsTo, Table: "profiles", Columns: userID, RefTable: "users", RefColumns: id},
+		{Kind: RelationHasOne, Table: "users", Columns: id, RefTable: "profiles", RefColumns: userID},
+		{Kind: RelationBelongsTo, Table: "orders", Columns: userID, RefTable: "users", RefColumns: id},
+		{Kind: RelationHasMany, Table: "users", Columns: id, RefTable: "orders", RefColumns: userID},
+		{Kind: RelationBelongsTo, Table: "user_roles", Columns: userID, RefTable: "users", RefColumns: id},
+		{Kind: RelationBelongsTo, Table: "user_roles", Columns: []string{"role_id"}, RefTable: "roles", RefColumns: id},
+		{
+			Kind: RelationManyToMany, Table: "users", Columns: id, RefTable: "roles", RefColumns: id,
+			JoinTable: "user_roles", JoinColumns: userID, JoinRefColumns: []string{"role_id"},
+		},
+		{
+			Kind: RelationManyToMany, Table: "roles", Columns: id, RefTable: "users", RefColumns: id,
+			JoinTable: "user_roles", JoinColumns: []string{"role_id"}, JoinRefColumns: userID,
+		},
+	}
+
+	actual := buildRelations(tables)
+	assert.Equal(t, expected, actual)
+	assert.Len(t, relationsOf(actual, "users"), 3)
+}
+
+func TestAddRelationFields(t *testing.T) {
+	sqls := []string{
+		"CREATE TABLE users (id BIGINT PRIMARY KEY)",
+		"CREATE TABLE profiles (id BIGINT PRIMARY KEY, user_id BIGINT UNIQUE REFERENCES users (id))",
+		"CREATE TABLE orders (id BIGINT PRIMARY KEY, user_id BIGINT, FOREIGN KEY (user_id) REFERENCES users (id))",
+		"CREATE TABLE roles (id BIGINT PRIMARY KEY)",
+		"CREATE TABLE user_roles (user_id BIGINT, role_id BIGINT, PRIMARY KEY (user_id, role_id), " +
+			"FOREIGN KEY (user_id) REFERENCES users, FOREIGN KEY (role_id) REFERENCES roles)",
+	}
+	schema := &Schema{}
+	for _, sql := range sqls {
+		table, err := extractTableStruct(sql, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		schema.Tables = append(schema.Tables, table)
+	}
+
+	files, err := Generate(schema, GeneratorOptions{Tags: []string{"json"}, Package: "models", Relations: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"Profiles *Profiles `gorm:\"foreignKey:UserID;references:ID\"`",
+		"Orders   []*Orders `gorm:\"foreignKey:UserID;references:ID\"`",
+		"Roles    []*Roles  `gorm:\"many2many:user_roles;foreignKey:ID;joinForeignKey:UserID;references:ID;joinReferences:RoleID\"`",
+		"User   *Users `gorm:\"foreignKey:UserID;references:ID\"`",
+		"Users []*Users `gorm:\"many2many:user_roles;foreignKey:ID;joinForeignKey:RoleID;references:ID;joinReferences:UserID\"`",
+		"Role   *Roles `gorm:\"foreignKey:RoleID;references:ID\"`",
+	}
+	for i, field := range expected {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			assert.Contains(t, string(files[0].Content), field)
+		})
+	}
+
+	files, err = Generate(schema, GeneratorOptions{Package: "models"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotContains(t, string(files[0].Content), "gorm:")
+}
@@ -0,0 +1,359 @@
+package sqlconverter
+
+// Schema is the in-memory model of the tables, the statements of a script are applied to it in order
+// so that it reflects the final structure of every table
+type Schema struct {
+	Tables []*TableStruct
+}
+
+// GetTable returns the table named name, nil if there is no such table
+func (s *Schema) GetTable(name string) *TableStruct {
+	for _, table := range s.Tables {
+		if table.TableName == name {
+			return table
+		}
+	}
+	return nil
+}
+
+// Apply applies the statement to the schema, statements on unknown tables, columns or indexes are ignored
+func (s *Schema) Apply(stmt Statement) {
+	switch stmt := stmt.(type) {
+	case *CreateTableStmt:
+		s.createTable(stmt)
+	case *CreateViewStmt:
+		// mysqldump creates a stub table for every view before the view itself
+		s.removeTable(stmt.View)
+	case *CreateIndexStmt:
+		if table := s.GetTable(stmt.Table); table != nil {
+			table.addIndex(stmt.Index.toIndexInfo())
+		}
+	case *AlterTableStmt:
+		s.alterTable(stmt)
+	case *RenameTableStmt:
+		for _, rename := range stmt.Renames {
+			s.renameTable(rename.Old, rename.New)
+		}
+	case *DropTableStmt:
+		for _, table := range stmt.Tables {
+			s.removeTable(table)
+		}
+	case *DropIndexStmt:
+		for _, table := range s.Tables {
+			if stmt.Table == "" || table.TableName == stmt.Table {
+				table.dropIndex(stmt.Name)
+			}
+		}
+	case *CommentStmt:
+		s.applyComment(stmt)
+	}
+}
+
+// AddTable adds the table, an existing table of the same name is replaced in place
+func (s *Schema) AddTable(table *TableStruct) {
+	for i, t := range s.Tables {
+		if t.TableName == table.TableName {
+			s.Tables[i] = table
+			return
+		}
+	}
+	s.Tables = append(s.Tables, table)
+}
+
+// createTable adds the table of the statement, an existing table of the same name is kept
+// if the statement is CREATE TABLE IF NOT EXISTS
+func (s *Schema) createTable(stmt *CreateTableStmt) {
+	if stmt.IfNotExists && s.GetTable(stmt.Table) != nil {
+		return
+	}
+	s.AddTable(stmt.toTableStruct())
+}
+
+func (s *Schema) removeTable(name string) {
+	for i, table := range s.Tables {
+		if table.TableName == name {
+			s.Tables = append(s.Tables[:i], s.Tables[i+1:]...)
+			return
+		}
+	}
+}
+
+// renameTable renames the table and the references to it
+func (s *Schema) renameTable(old, name string) {
+	table := s.GetTable(old)
+	if table == nil {
+		return
+	}
+	table.TableName = name
+	for _, t := range s.Tables {
+		for _, foreignKey := range t.ForeignKeys {
+			if foreignKey.RefTable == old {
+				foreignKey.RefTable = name
+			}
+		}
+	}
+}
+
+// renameColumn renames the column of the table and the references to it
+func (s *Schema) renameColumn(table *TableStruct, old, name string) {
+	if old == name {
+		return
+	}
+	table.renameColumn(old, name)
+	for _, t := range s.Tables {
+		for _, foreignKey := range t.ForeignKeys {
+			if foreignKey.RefTable == table.TableName {
+				renameIn(foreignKey.RefColumns, old, name)
+			}
+		}
+	}
+}
+
+// applyComment sets the comment of a table or field
+func (s *Schema) applyComment(stmt *CommentStmt) {
+	table := s.GetTable(stmt.Table)
+	if table == nil {
+		return
+	}
+	if stmt.Column == "" {
+		table.Comment = stmt.Comment
+		return
+	}
+	if field := table.GetField(stmt.Column); field != nil {
+		field.FieldComment = stmt.Comment
+	}
+}
+
+func (s *Schema) alterTable(stmt *AlterTableStmt) {
+	table := s.GetTable(stmt.Table)
+	if table == nil {
+		return
+	}
+	for _, spec := range stmt.Specs {
+		switch spec.Action {
+		case AlterAddColumn:
+			table.addColumn(spec.Column, spec.First, spec.After)
+		case AlterModifyColumn:
+			if table.GetField(spec.Name) == nil {
+				continue
+			}
+			s.renameColumn(table, spec.Name, spec.Column.Name)
+			table.addColumn(spec.Column, spec.First, spec.After)
+		case AlterDropColumn:
+			table.dropColumn(spec.Name)
+		case AlterRenameColumn:
+			if table.GetField(spec.Name) != nil {
+				s.renameColumn(table, spec.Name, spec.NewName)
+			}
+		case AlterAddIndex:
+			table.addIndex(spec.Constraint.toIndexInfo())
+		case AlterAddForeignKey:
+			table.ForeignKeys = append(table.ForeignKeys, spec.ForeignKey.toForeignKeyInfo())
+		case AlterDropIndex:
+			table.dropIndex(spec.Name)
+		case AlterDropPrimaryKey:
+			table.PrimaryKey = nil
+		case AlterDropForeignKey:
+			table.dropForeignKey(spec.Name)
+		case AlterDropConstraint:
+			if !table.dropForeignKey(spec.Name) {
+				table.dropIndex(spec.Name)
+			}
+		case AlterRenameIndex:
+			table.renameIndex(spec.Name, spec.NewName)
+		case AlterRenameTable:
+			s.renameTable(table.TableName, spec.NewName)
+		default:
+			if field := table.GetField(spec.Name); field != nil {
+				table.alterField(field, spec)
+			}
+		}
+	}
+}
+
+// alterField applies the specifications of ALTER COLUMN which change a part of the field
+func (t *TableStruct) alterField(field *FieldInfo, spec *AlterSpec) {
+	switch spec.Action {
+	case AlterSetType:
+		field.FieldType, field.Array = spec.Type.Name, spec.Type.Array
+		field.Length, field.Precision, field.Scale = 0, 0, 0
+		field.setTypeArgs(spec.Type.Args)
+	case AlterSetNotNull:
+		field.Nullable = false
+	case AlterDropNotNull:
+		field.Nullable = !t.IsPrimaryKey(field.FieldName)
+	case AlterSetDefault:
+		field.Default = spec.Default
+		if isSequenceDefault(*spec.Default) {
+			field.AutoIncrement = true
+		}
+	case AlterDropDefault:
+		if field.Default != nil && isSequenceDefault(*field.Default) {
+			field.AutoIncrement = false
+		}
+		field.Default = nil
+	}
+}
+
+// addColumn adds the field of the column, an existing field of the same name is replaced in place
+// unless the position is given by first or after
+func (t *TableStruct) addColumn(column *ColumnDef, first bool, after string) {
+	field := column.toFieldInfo()
+	if field == nil {
+		return
+	}
+	i := t.fieldIndex(field.FieldName)
+	if i >= 0 {
+		t.Fields[i] = field
+	}
+	if i < 0 || first || after != "" {
+		t.moveField(field, first, after)
+	}
+	if t.IsPrimaryKey(field.FieldName) {
+		field.Nullable = false
+	}
+	for _, index := range column.indexes() {
+		t.addIndex(index)
+	}
+	if foreignKey := column.foreignKey(); foreignKey != nil {
+		t.ForeignKeys = append(t.ForeignKeys, foreignKey)
+	}
+}
+
+func (t *TableStruct) fieldIndex(name string) int {
+	for i, field := range t.Fields {
+		if field.FieldName == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// moveField places the field first, after the field named after, or last
+func (t *TableStruct) moveField(field *FieldInfo, first bool, after string) {
+	if i := t.fieldIndex(field.FieldName); i >= 0 {
+		t.Fields = append(t.Fields[:i], t.Fields[i+1:]...)
+	}
+	i := len(t.Fields)
+	if first {
+		i = 0
+	} else if j := t.fieldIndex(after); j >= 0 {
+		i = j + 1
+	}
+	t.Fields = append(t.Fields, nil)
+	copy(t.Fields[i+1:], t.Fields[i:])
+	t.Fields[i] = field
+}
+
+// dropColumn removes the field and its parts of the keys, the keys left without columns are removed
+func (t *TableStruct) dropColumn(name string) {
+	i := t.fieldIndex(name)
+	if i < 0 {
+		return
+	}
+	t.Fields = append(t.Fields[:i], t.Fields[i+1:]...)
+
+	if t.PrimaryKey != nil {
+		t.PrimaryKey.Columns = removeFrom(t.PrimaryKey.Columns, name)
+		if len(t.PrimaryKey.Columns) == 0 {
+			t.PrimaryKey = nil
+		}
+	}
+	var indexes []*IndexInfo
+	for _, index := range t.Indexes {
+		index.Columns = removeFrom(index.Columns, name)
+		if len(index.Columns) > 0 {
+			indexes = append(indexes, index)
+		}
+	}
+	t.Indexes = indexes
+
+	var foreignKeys []*ForeignKeyInfo
+	for _, foreignKey := range t.ForeignKeys {
+		if len(removeFrom(foreignKey.Columns, name)) == len(foreignKey.Columns) {
+			foreignKeys = append(foreignKeys, foreignKey)
+		}
+	}
+	t.ForeignKeys = foreignKeys
+}
+
+// renameColumn renames the field and the columns of the keys of the table
+func (t *TableStruct) renameColumn(old, name string) {
+	if field := t.GetField(old); field != nil {
+		field.FieldName = name
+	}
+	if t.PrimaryKey != nil {
+		renameIn(t.PrimaryKey.Columns, old, name)
+	}
+	for _, index := range t.Indexes {
+		renameIn(index.Columns, old, name)
+	}
+	for _, foreignKey := range t.ForeignKeys {
+		renameIn(foreignKey.Columns, old, name)
+		// the table references itself
+		if foreignKey.RefTable == t.TableName {
+			renameIn(foreignKey.RefColumns, old, name)
+		}
+	}
+}
+
+// dropIndex removes the index or the primary key named name
+func (t *TableStruct) dropIndex(name string) bool {
+	if t.PrimaryKey != nil && t.PrimaryKey.Name == name {
+		t.PrimaryKey = nil
+		return true
+	}
+	for i, index := range t.Indexes {
+		if index.Name == name {
+			t.Indexes = append(t.Indexes[:i], t.Indexes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// dropForeignKey removes the foreign key named name
+func (t *TableStruct) dropForeignKey(name string) bool {
+	for i, foreignKey := range t.ForeignKeys {
+		if foreignKey.Name == name {
+			t.ForeignKeys = append(t.ForeignKeys[:i], t.ForeignKeys[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// renameIndex renames the index, the primary key or the foreign key named old
+func (t *TableStruct) renameIndex(old, name string) {
+	if t.PrimaryKey != nil && t.PrimaryKey.Name == old {
+		t.PrimaryKey.Name = name
+	}
+	for _, index := range t.Indexes {
+		if index.Name == old {
+			index.Name = name
+		}
+	}
+	for _, foreignKey := range t.ForeignKeys {
+		if foreignKey.Name == old {
+			foreignKey.Name = name
+		}
+	}
+}
+
+func removeFrom(columns []string, name string) []string {
+	var res []string
+	for _, column := range columns {
+		if column != name {
+			res = append(res, column)
+		}
+	}
+	return res
+}
+
+func renameIn(columns []string, old, name string) {
+	for i, column := range columns {
+		if column == old {
+			columns[i] = name
+		}
+	}
+}
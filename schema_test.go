@@ -0,0 +1,76 @@
+package sqlconverter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaApply(t *testing.T) {
+	sqls := []string{
+		"CREATE TABLE users (id int PRIMARY KEY, mail varchar(32), nick varchar(16), KEY idx_nick (nick))",
+		"CREATE TABLE orders (id int PRIMARY KEY, uid int, CONSTRAINT fk_user FOREIGN KEY (uid) REFERENCES users (id))",
+		"CREATE TABLE logs (id int)",
+		"ALTER TABLE users ADD COLUMN age int NOT NULL AFTER id, DROP COLUMN nick, CHANGE mail email varchar(64) NOT NULL",
+		"ALTER TABLE users RENAME COLUMN id TO uid, ADD UNIQUE KEY uk_email (email)",
+		"CREATE INDEX idx_age ON users (age)",
+		"RENAME TABLE users TO members",
+		"ALTER TABLE orders MODIFY uid bigint FIRST, DROP FOREIGN KEY fk_user",
+		"DROP TABLE IF EXISTS logs, unknown",
+		"ALTER TABLE unknown DROP COLUMN id",
+	}
+	schema := &Schema{}
+	for _, sql := range sqls {
+		stmt, err := parseStatement(sql, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		schema.Apply(stmt)
+	}
+
+	if !assert.Len(t, schema.Tables, 2) {
+		return
+	}
+	members, orders := schema.Tables[0], schema.Tables[1]
+	assert.Equal(t, "members", members.TableName)
+	var names []string
+	for _, field := range members.Fields {
+		names = append(names, field.FieldName)
+	}
+	assert.Equal(t, []string{"uid", "age", "email"}, names)
+	assert.Equal(t, 64, members.GetField("email").Length)
+	assert.False(t, members.GetField("email").Nullable)
+	assert.Equal(t, []string{"uid"}, members.PrimaryKey.Columns)
+	assert.Equal(t, []*IndexInfo{
+		{Name: "uk_email", Kind: IndexUnique, Columns: []string{"email"}},
+		{Name: "idx_age", Kind: IndexNormal, Columns: []string{"age"}},
+	}, members.Indexes)
+
+	assert.Equal(t, "uid", orders.Fields[0].FieldName)
+	assert.Equal(t, "bigint", orders.Fields[0].FieldType)
+	assert.Empty(t, orders.ForeignKeys)
+}
+
+func TestSchemaApplyReferences(t *testing.T) {
+	sqls := []string{
+		"CREATE TABLE users (id int PRIMARY KEY)",
+		"CREATE TABLE orders (id int, uid int REFERENCES users (id))",
+		"ALTER TABLE users RENAME COLUMN id TO user_id, RENAME TO members",
+		"ALTER TABLE orders ALTER COLUMN id SET DEFAULT nextval('orders_id_seq'), ADD PRIMARY KEY (id)",
+	}
+	schema := &Schema{}
+	for _, sql := range sqls {
+		stmt, err := parseStatement(sql, Options{Dialect: PostgreSQL})
+		if err != nil {
+			t.Fatal(err)
+		}
+		schema.Apply(stmt)
+	}
+
+	orders := schema.GetTable("orders")
+	assert.Equal(t, []*ForeignKeyInfo{
+		{Columns: []string{"uid"}, RefTable: "members", RefColumns: []string{"user_id"}},
+	}, orders.ForeignKeys)
+	assert.True(t, orders.GetField("id").AutoIncrement)
+	assert.False(t, orders.GetField("id").Nullable)
+}
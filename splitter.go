@@ -0,0 +1,86 @@
+package sqlconverter
+
+import (
+	"strings"
+	"unicode"
+)
+
+const defaultDelimiter = ";"
+
+// splitStatements splits a sql script into statements. Strings, quoted identifiers and comments
+// never split a statement, the delimiter can be changed by the DELIMITER command of mysql client,
+// and a GO line ends a statement as well when the dialect uses batch separators.
+// Comments before a statement and the delimiters are not part of the returned statements.
+func splitStatements(content string, cfg lexerConfig) ([]string, error) {
+	lexer := newLexer(content, cfg)
+	lexer.keepComments = true
+
+	var (
+		res        []string
+		delimiter  = defaultDelimiter
+		start, end = -1, -1
+	)
+	flush := func() {
+		if start >= 0 {
+			res = append(res, strings.TrimSpace(string(lexer.src[start:end])))
+		}
+		start, end = -1, -1
+	}
+
+	for {
+		lexer.skipSpace()
+		if lexer.hasPrefix(delimiter) {
+			lexer.pos += len([]rune(delimiter))
+			flush()
+			continue
+		}
+		if start < 0 && lexer.hasPrefix("DELIMITER") && isDelimiterCommand(lexer) {
+			lexer.pos += len("DELIMITER")
+			if d := strings.TrimSpace(lexer.restOfLine()); d != "" {
+				delimiter = d
+			}
+			continue
+		}
+
+		tok, err := lexer.Next()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case tok.Kind == TokenEOF:
+			flush()
+			return res, nil
+		case tok.Kind == TokenComment:
+			continue
+		case cfg.batchSeparator && tok.IsKeyword("GO") && isBatchSeparator(lexer, tok):
+			lexer.restOfLine()
+			flush()
+			continue
+		}
+		if start < 0 {
+			start = tok.Pos
+		}
+		end = tok.End
+	}
+}
+
+// isDelimiterCommand reports whether the DELIMITER at the current position is a whole word
+func isDelimiterCommand(lexer *Lexer) bool {
+	next := lexer.pos + len("DELIMITER")
+	return next < len(lexer.src) && (lexer.src[next] == ' ' || lexer.src[next] == '\t')
+}
+
+// isBatchSeparator reports whether the GO token is alone on its line, an optional count may follow it
+func isBatchSeparator(lexer *Lexer, tok Token) bool {
+	for i := tok.Pos - 1; i >= 0 && lexer.src[i] != '\n'; i-- {
+		if !unicode.IsSpace(lexer.src[i]) {
+			return false
+		}
+	}
+	for i := tok.End; i < len(lexer.src) && lexer.src[i] != '\n'; i++ {
+		if r := lexer.src[i]; !unicode.IsSpace(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
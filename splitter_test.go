@@ -0,0 +1,57 @@
+package sqlconverter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitStatements(t *testing.T) {
+	inputs := []string{
+		"CREATE TABLE a (x int COMMENT 'a; b');CREATE TABLE `b;c` (y int)",
+		"-- first; comment\nCREATE TABLE a (x int) /* block; comment */;\n# hash; comment\nSELECT 1",
+		"/*!40101 SET NAMES utf8 */;\n/*!50001 CREATE TABLE `v` (`id` int)*/;",
+		"DELIMITER ;;\nCREATE TRIGGER t BEFORE INSERT ON a FOR EACH ROW BEGIN SET NEW.x = 1; END ;;\nDELIMITER ;\nSELECT 2;",
+		"DELIMITER //\nCREATE PROCEDURE p() BEGIN SELECT 1; END//\nDELIMITER ;\n;;SELECT 3",
+		"CREATE TABLE a (x int)",
+	}
+	expecteds := [][]string{
+		{"CREATE TABLE a (x int COMMENT 'a; b')", "CREATE TABLE `b;c` (y int)"},
+		{"CREATE TABLE a (x int)", "SELECT 1"},
+		{"SET NAMES utf8", "CREATE TABLE `v` (`id` int)"},
+		{"CREATE TRIGGER t BEFORE INSERT ON a FOR EACH ROW BEGIN SET NEW.x = 1; END", "SELECT 2"},
+		{"CREATE PROCEDURE p() BEGIN SELECT 1; END", "SELECT 3"},
+		{"CREATE TABLE a (x int)"},
+	}
+
+	for i, input := range inputs {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			actual, err := splitStatements(input, MySQL.lexerConfig())
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, expecteds[i], actual)
+		})
+	}
+}
+
+func TestSplitStatementsBatchSeparator(t *testing.T) {
+	input := "CREATE TABLE [a;b] (x int)\nGO\nSELECT 'GO'\n  go 2\nCREATE TABLE c (GO int)"
+	expected := []string{
+		"CREATE TABLE [a;b] (x int)",
+		"SELECT 'GO'",
+		"CREATE TABLE c (GO int)",
+	}
+
+	actual, err := splitStatements(input, SQLServer.lexerConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestSplitStatementsError(t *testing.T) {
+	_, err := splitStatements("CREATE TABLE a (x int COMMENT 'a; b)", MySQL.lexerConfig())
+	assert.Error(t, err)
+}
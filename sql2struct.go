@@ -1,69 +1,38 @@
-package main
+package sqlconverter
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"go/ast"
+	"go/format"
+	goparser "go/parser"
+	"go/token"
+	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
-	"unicode"
 )
 
-type Element int32
+// StdoutPath is the output file which writes the standard output
+const StdoutPath = "-"
 
-const (
-	ElementTableDefault Element = iota
-	ElementTable
-	ElementField
-	ElementFieldType
-	ElementComment
-)
-
-func (t Element) String() string {
-	switch t {
-	case ElementTableDefault:
-		return "unknown"
-	case ElementTable:
-		return "table"
-	case ElementField:
-		return "field"
-	case ElementFieldType:
-		return "field type"
-	case ElementComment:
-		return "comment"
-	}
-	return ""
-}
-
-type GroupFlag int32
-
-const (
-	GroupFlagUnknown GroupFlag = iota
-	GroupFlagDefault
-	GroupFlagSingleQuote
-	GroupFlagBackQuote
+// stdout receives the generated code of StdoutPath, and stderr the informational messages
+var (
+	stdout io.Writer = os.Stdout
+	stderr io.Writer = os.Stderr
 )
 
-func (t GroupFlag) String() string {
-	switch t {
-	case GroupFlagUnknown:
-		return "未知"
-	case GroupFlagDefault:
-		return "空格或括号"
-	case GroupFlagSingleQuote:
-		return "单引号"
-	case GroupFlagBackQuote:
-		return "反引号"
-	}
-	return ""
-}
-
 type WriteMode string
 
 const (
-	NONE      WriteMode = ""
+	NONE      WriteMode = "" // MERGE into an existing go file and APPEND to the other files
 	APPEND    WriteMode = "APPEND"
 	OVERWRITE WriteMode = "OVERWRITE"
+	MERGE     WriteMode = "MERGE" // replace the generated structs of the existing go file and keep the rest of it
 )
 
 func (mode WriteMode) IsAllowed() bool {
@@ -77,7 +46,7 @@ func (mode WriteMode) IsAllowed() bool {
 
 type ConvertFunc func(string) string
 
-var AllowedMode = []WriteMode{APPEND, OVERWRITE}
+var AllowedMode = []WriteMode{APPEND, OVERWRITE, MERGE}
 
 var defaultConvertFunc = func(source string) string {
 	strs := strings.Split(source, "_")
@@ -100,8 +69,115 @@ var defaultConvertFunc = func(source string) string {
 }
 
 type TableStruct struct {
-	TableName string
-	Fields    []*FieldInfo
+	TableName   string
+	Comment     string
+	Fields      []*FieldInfo
+	PrimaryKey  *IndexInfo
+	Indexes     []*IndexInfo // the unique constraints and secondary indexes in declaration order
+	ForeignKeys []*ForeignKeyInfo
+	SourceFile  string // the file the table is created in or the driver of the database it is read from, empty otherwise
+}
+
+type IndexKind string
+
+const (
+	IndexPrimary  IndexKind = "PRIMARY"
+	IndexUnique   IndexKind = "UNIQUE"
+	IndexNormal   IndexKind = "INDEX"
+	IndexFulltext IndexKind = "FULLTEXT"
+	IndexSpatial  IndexKind = "SPATIAL"
+)
+
+type IndexInfo struct {
+	Name    string
+	Kind    IndexKind
+	Columns []string // the ordered column names of the index
+}
+
+type ForeignKeyInfo struct {
+	Name       string
+	Columns    []string
+	RefTable   string
+	RefColumns []string // empty when the primary key of RefTable is referenced
+	OnDelete   string
+	OnUpdate   string
+}
+
+// GetField returns the field named name, nil if the table has no such field
+func (t *TableStruct) GetField(name string) *FieldInfo {
+	for _, field := range t.Fields {
+		if field.FieldName == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// IsPrimaryKey reports whether the field is part of the primary key
+func (t *TableStruct) IsPrimaryKey(field string) bool {
+	if t.PrimaryKey == nil {
+		return false
+	}
+	for _, column := range t.PrimaryKey.Columns {
+		if column == field {
+			return true
+		}
+	}
+	return false
+}
+
+// UniqueIndexes returns the unique constraints of the table, the primary key is not included
+func (t *TableStruct) UniqueIndexes() []*IndexInfo {
+	var res []*IndexInfo
+	for _, index := range t.Indexes {
+		if index.Kind == IndexUnique {
+			res = append(res, index)
+		}
+	}
+	return res
+}
+
+// isUnique reports whether the columns are exactly the primary key or one of the unique constraints
+func (t *TableStruct) isUnique(columns []string) bool {
+	if t.PrimaryKey != nil && sameColumns(t.PrimaryKey.Columns, columns) {
+		return true
+	}
+	for _, index := range t.UniqueIndexes() {
+		if sameColumns(index.Columns, columns) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameColumns(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[string]struct{})
+	for _, column := range a {
+		set[column] = struct{}{}
+	}
+	for _, column := range b {
+		if _, exist := set[column]; !exist {
+			return false
+		}
+	}
+	return true
+}
+
+// addIndex adds the index, a primary key replaces the existing one and the columns become not null
+func (t *TableStruct) addIndex(index *IndexInfo) {
+	if index.Kind != IndexPrimary {
+		t.Indexes = append(t.Indexes, index)
+		return
+	}
+	t.PrimaryKey = index
+	for _, column := range index.Columns {
+		if field := t.GetField(column); field != nil {
+			field.Nullable = false
+		}
+	}
 }
 
 func (t *TableStruct) String() string {
@@ -126,9 +202,20 @@ func (s *SS) String() string {
 }
 
 type FieldInfo struct {
-	FieldName    string // the name of field
-	FieldType    string // the type of field
-	FieldComment string
+	FieldName     string // the name of field
+	FieldType     string // the type of field
+	FieldComment  string
+	Array         bool // the field is an array of FieldType
+	Nullable      bool
+	Default       *string // the default value expression, nil when the field has no default
+	AutoIncrement bool
+	Unsigned      bool
+	Length        int // the length of string types or the display width of integer types
+	Precision     int // the precision of decimal, float and fractional seconds of time types
+	Scale         int
+	Charset       string
+	Collation     string
+	OnUpdate      string // the ON UPDATE expression
 }
 
 type CreateTableSQLParser struct {
@@ -142,10 +229,30 @@ type CreateTableSQLParser struct {
 	FieldNamePrefix string
 	FieldNameSuffix string
 	SqlFile         string
+	SqlFiles        []string // more sql files, directories or glob patterns read after SqlFile
+	DSN             string   // the data source name of a running database whose tables are read
+	Driver          string   // the registered database/sql driver which opens DSN, default: the one of Dialect
 	TargetDir       string
-	Mode            WriteMode
-
-	structs []*SS
+	OutputFile      string    // the file the code is written to, "-" for the standard output, default: TargetDir/generator.go
+	PackageName     string    // the package of the generated code, default: the package or the name of the output directory
+	TemplateFile    string    // the text/template file which renders the schema instead of the go structs
+	PerTable        bool      // generate a file for each table in TargetDir instead of a single file
+	FileName        string    // the name of the file in TargetDir, or the pattern of the file names of the tables, see DefaultTableFileName
+	DryRun          bool      // print the files which would be written instead of writing them
+	Diff            bool      // print the unified diff of the files on disk and the ones which would be written instead of writing them
+	Mode            WriteMode // default: MERGE for an existing go file and APPEND otherwise
+	FoldIdentCase   bool      // fold table and column names to lower case instead of keeping the case of the source
+	Relations       bool      // add the association fields of the relations between the tables with their gorm tags
+	Dialect         Dialect
+	NullStrategy    NullStrategy                       // how nullable fields are typed, default: NONE
+	NullOverrides   map[MappedGoFieldType]NullStrategy // the null strategy of specific go types
+
+	sources   []*sqlSource
+	schema    Schema
+	relations []*Relation
+	structs   []*SS
+
+	templateText string // the text of the template, which takes precedence over TemplateFile
 }
 
 func (parser *CreateTableSQLParser) SetDefault() *CreateTableSQLParser {
@@ -159,116 +266,440 @@ func (parser *CreateTableSQLParser) SetDefault() *CreateTableSQLParser {
 		d, _ := filepath.Split(parser.SqlFile)
 		parser.TargetDir = d
 	}
+	if parser.TargetDir == "" {
+		parser.TargetDir = "."
+	}
 	parser.TargetDir = strings.TrimSuffix(parser.TargetDir, "/")
 	if parser.Converter == nil {
 		parser.Converter = defaultConvertFunc
 	}
-	if parser.Mode == NONE {
-		parser.Mode = APPEND
+	if parser.Dialect == "" {
+		parser.Dialect = sourceDialect(parser.SqlFile)
+	}
+	if parser.Dialect == "" {
+		parser.Dialect = MySQL
+	}
+	if parser.Driver == "" {
+		parser.Driver = parser.Dialect.defaultDriver()
+	}
+	if parser.NullStrategy == "" {
+		parser.NullStrategy = NullNone
+	}
+	if parser.FileName == "" {
+		parser.FileName = DefaultFileName
+		if parser.PerTable {
+			parser.FileName = DefaultTableFileName
+		}
+	}
+	if parser.PackageName == "" {
+		parser.PackageName = dirPackageName(parser.outputDir())
 	}
 	return parser
 }
 
+// outputDir returns the directory of OutputFile, TargetDir when the code is not written to a file
+func (parser *CreateTableSQLParser) outputDir() string {
+	if parser.OutputFile == "" || parser.OutputFile == StdoutPath {
+		return parser.TargetDir
+	}
+	return filepath.Dir(parser.OutputFile)
+}
+
 func (parser *CreateTableSQLParser) Parse() error {
 	parser.SetDefault()
+	if err := parser.validate(); err != nil {
+		return err
+	}
+	if err := parser.load(); err != nil {
+		return err
+	}
 	if err := parser.parseSQL(); err != nil {
 		return err
 	}
-	return parser.output(string(parser.format()))
+	parser.report()
+	files, err := parser.files()
+	if err != nil {
+		return err
+	}
+	if parser.OutputFile == StdoutPath {
+		_, err := stdout.Write(files[0].Content)
+		return err
+	}
+	for _, file := range files {
+		targetFile := parser.outputFile()
+		if parser.PerTable {
+			targetFile = parser.TargetDir + "/" + file.Name
+		}
+		if err := parser.output(targetFile, file.Content); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func (parser *CreateTableSQLParser) parseSQL() error {
-	for _, ele := range parser.Sqls {
-		table, err := extractTableStruct(strings.ToLower(ele))
+// validate checks the options which conflict with each other before the sources are read
+func (parser *CreateTableSQLParser) validate() error {
+	if !IsPackageName(parser.PackageName) {
+		return fmt.Errorf("invalid package name %q", parser.PackageName)
+	}
+	if parser.PerTable && parser.OutputFile != "" {
+		return fmt.Errorf("the output file can not be set when a file is generated for each table")
+	}
+	if parser.OutputFile == StdoutPath && (parser.DryRun || parser.Diff) {
+		return fmt.Errorf("the standard output can not be previewed by dry run or diff")
+	}
+	return nil
+}
+
+// Inspect writes the schema IR of the tables to OutputFile, which is overwritten, default: the standard output
+func (parser *CreateTableSQLParser) Inspect() error {
+	parser.SetDefault()
+	if err := parser.load(); err != nil {
+		return err
+	}
+	if err := parser.parseSQL(); err != nil {
+		return err
+	}
+	ir := NewIR(&parser.schema, parser.Dialect)
+	if parser.OutputFile == "" || parser.OutputFile == StdoutPath {
+		return ir.Write(stdout)
+	}
+	file, err := os.Create(parser.OutputFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	fmt.Fprintf(stderr, "Output: %s\n", parser.OutputFile)
+	return ir.Write(file)
+}
+
+// report prints the file each table is created in
+func (parser *CreateTableSQLParser) report() {
+	for _, table := range parser.schema.Tables {
+		if table.SourceFile != "" {
+			fmt.Fprintf(stderr, "Table: %s, from: %s\n", table.TableName, table.SourceFile)
+		}
+	}
+}
+
+// load reads the tables of DSN and the statements of SqlFile and SqlFiles, which are split after the dialect is known
+func (parser *CreateTableSQLParser) load() error {
+	if parser.DSN != "" {
+		tables, err := introspectDSN(parser.Driver, parser.DSN, parser.parseOptions())
 		if err != nil {
-			return err
+			return fmt.Errorf("introspect database failed, err: %v", err)
 		}
-		if table == nil {
-			continue
+		parser.sources = append(parser.sources, &sqlSource{Path: parser.Driver, Tables: tables})
+	}
+	var paths []string
+	if parser.SqlFile != "" {
+		paths = append(paths, parser.SqlFile)
+	}
+	sources, err := readSources(append(paths, parser.SqlFiles...), parser.parseOptions())
+	if err != nil {
+		return fmt.Errorf("read sql failed, err: %v", err)
+	}
+	parser.sources = append(parser.sources, sources...)
+	return nil
+}
+
+// parseSQL applies Sqls and then the statements or tables of the loaded files to the schema
+func (parser *CreateTableSQLParser) parseSQL() error {
+	sources := append([]*sqlSource{{Sqls: parser.Sqls}}, parser.sources...)
+	for _, source := range sources {
+		for _, table := range source.Tables {
+			table.SourceFile = source.Path
+			parser.schema.AddTable(table)
+		}
+		for _, ele := range source.Sqls {
+			stmt, err := parseStatement(ele, parser.parseOptions())
+			if err != nil {
+				if source.Path != "" {
+					return fmt.Errorf("%s: %v", source.Path, err)
+				}
+				return err
+			}
+			parser.schema.Apply(stmt)
+			if create, ok := stmt.(*CreateTableStmt); ok {
+				if table := parser.schema.GetTable(create.Table); table != nil && table.SourceFile == "" {
+					table.SourceFile = source.Path
+				}
+			}
 		}
-		parser.structs = append(parser.structs, parser.fromTableStruct2SS(table, parser.getConvertFunc()))
 	}
+	parser.generate()
 	return nil
 }
 
-func (parser *CreateTableSQLParser) format() []byte {
-	var res []string
-	res = append(res, "package main\n")
-	for _, ss := range parser.structs {
-		res = append(res, parser.formatOne(ss))
+// generate derives the relations and the structs of the tables of the schema, the structs have the association
+// fields of the relations when Relations is set
+func (parser *CreateTableSQLParser) generate() {
+	parser.relations = buildRelations(parser.schema.Tables)
+	for _, table := range parser.schema.Tables {
+		parser.structs = append(parser.structs, parser.fromTableStruct2SS(table, parser.getConvertFunc()))
+	}
+	if parser.Relations {
+		parser.addRelationFields()
 	}
-	return []byte(strings.Join(res, "\n\n"))
 }
 
-func (parser *CreateTableSQLParser) formatOne(ss *SS) string {
-	var tmp [][]string
-	for _, field := range ss.Fields {
-		tmp = append(tmp, []string{
-			field.FieldName, field.FiledType, field.Comment,
-		})
+func (parser *CreateTableSQLParser) parseOptions() Options {
+	return Options{
+		Dialect:       parser.Dialect,
+		FoldIdentCase: parser.FoldIdentCase,
 	}
+}
 
-	var reverseTmpWithPadding [][]string
-	for j := 0; j < 2; j++ {
-		var cols []string
-		for i := 0; i < len(tmp); i++ {
-			cols = append(cols, tmp[i][j])
+// format builds the go file of the structs with go/ast and formats it by go/format, the imports are derived
+// from the package qualifiers of the field types, and each struct is marked by TableMarker with its table.
+// An error is returned when the file does not parse. The template renders the file instead when it is given
+func (parser *CreateTableSQLParser) format() ([]byte, error) {
+	if parser.templateText != "" || parser.TemplateFile != "" {
+		return parser.render()
+	}
+	var decls []ast.Decl
+	for _, ss := range parser.structs {
+		decl, err := ss.decl()
+		if err != nil {
+			return nil, err
 		}
-
-		reverseTmpWithPadding = append(reverseTmpWithPadding, colPadding(cols))
+		decls = append(decls, decl)
+	}
+	imports, err := declImports(decls)
+	if err != nil {
+		return nil, err
 	}
 
-	var res []string
-	res = append(res, fmt.Sprintf("type %s struct {", ss.StructName))
-	for j := 0; j < len(reverseTmpWithPadding[0]); j++ {
-		res = append(res, fmt.Sprintf("\t%s %s %s", reverseTmpWithPadding[0][j], reverseTmpWithPadding[1][j], tmp[j][2]))
+	res := []string{fmt.Sprintf("package %s", parser.PackageName)}
+	if len(imports) > 0 {
+		var b bytes.Buffer
+		if err := format.Node(&b, token.NewFileSet(), importDecl(imports)); err != nil {
+			return nil, fmt.Errorf("format generated code failed, err: %v", err)
+		}
+		res = append(res, b.String())
 	}
-	res = append(res, "}")
+	for i, decl := range decls {
+		var b bytes.Buffer
+		fmt.Fprintf(&b, "// %s: %s\n", TableMarker, parser.schema.Tables[i].TableName)
+		if err := format.Node(&b, token.NewFileSet(), decl); err != nil {
+			return nil, fmt.Errorf("format generated code failed, err: %v", err)
+		}
+		res = append(res, b.String())
+	}
+	src, err := format.Source([]byte(strings.Join(res, "\n\n")))
+	if err != nil {
+		return nil, fmt.Errorf("generated code is invalid, err: %v", err)
+	}
+	return src, nil
+}
 
-	return strings.Join(res, "\n")
+// decl returns the type declaration of the struct, the names should be go identifiers and the types go type expressions
+func (ss *SS) decl() (*ast.GenDecl, error) {
+	if !token.IsIdentifier(ss.StructName) {
+		return nil, fmt.Errorf("invalid struct name %q", ss.StructName)
+	}
+	fields := &ast.FieldList{}
+	for _, field := range ss.Fields {
+		if !token.IsIdentifier(field.FieldName) {
+			return nil, fmt.Errorf("invalid field name %q of struct %s", field.FieldName, ss.StructName)
+		}
+		typ, err := goparser.ParseExpr(field.FiledType)
+		if err != nil {
+			return nil, fmt.Errorf("invalid type %q of field %s.%s, err: %v", field.FiledType, ss.StructName, field.FieldName, err)
+		}
+		f := &ast.Field{Names: []*ast.Ident{ast.NewIdent(field.FieldName)}, Type: typ}
+		if field.Comment != "" {
+			f.Tag = &ast.BasicLit{Kind: token.STRING, Value: field.Comment}
+		}
+		fields.List = append(fields.List, f)
+	}
+	return &ast.GenDecl{
+		Tok: token.TYPE,
+		Specs: []ast.Spec{&ast.TypeSpec{
+			Name: ast.NewIdent(ss.StructName),
+			Type: &ast.StructType{Fields: fields},
+		}},
+	}, nil
 }
 
-func colPadding(cols []string) []string {
-	blankPaddingFunc := func(n int) string {
-		var tmp []rune
-		for i := 0; i < n; i++ {
-			tmp = append(tmp, ' ')
+// declImports returns the sorted import paths of the qualified types used by the declarations
+func declImports(decls []ast.Decl) ([]string, error) {
+	set := make(map[string]struct{})
+	var err error
+	for _, decl := range decls {
+		ast.Inspect(decl, func(node ast.Node) bool {
+			sel, ok := node.(*ast.SelectorExpr)
+			if !ok || err != nil {
+				return err == nil
+			}
+			pkg, ok := sel.X.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			name := MappedGoFieldType(pkg.Name + "." + sel.Sel.Name)
+			path := name.importPath()
+			if path == "" {
+				err = fmt.Errorf("unknown package of type %s", name)
+				return false
+			}
+			set[path] = struct{}{}
+			return false
+		})
+		if err != nil {
+			return nil, err
 		}
-		return string(tmp)
 	}
-	maxLen := 0
-	for i := 0; i < len(cols); i++ {
-		maxLen = MaxInt(len(cols[i]), maxLen)
+	var res []string
+	for path := range set {
+		res = append(res, path)
 	}
+	sort.Strings(res)
+	return res, nil
+}
 
-	var res []string
-	for i := 0; i < len(cols); i++ {
-		res = append(res, cols[i]+blankPaddingFunc(maxLen-len(cols[i])))
+// importDecl returns the import declaration of the paths, which are parenthesized when there are several of them
+func importDecl(imports []string) *ast.GenDecl {
+	decl := &ast.GenDecl{Tok: token.IMPORT}
+	if len(imports) > 1 {
+		decl.Lparen = 1
 	}
-	return res
+	for _, path := range imports {
+		decl.Specs = append(decl.Specs, &ast.ImportSpec{Path: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(path)}})
+	}
+	return decl
+}
+
+// writeMode returns the mode the target file is written in, the code can not be appended to an existing go file
+// since the file would declare its package twice
+func (parser *CreateTableSQLParser) writeMode(target string, existing []byte) (WriteMode, error) {
+	existingGo := filepath.Ext(target) == ".go" && len(bytes.TrimSpace(existing)) > 0
+	switch {
+	case parser.Mode == NONE && existingGo:
+		return MERGE, nil
+	case parser.Mode == NONE:
+		return APPEND, nil
+	case parser.Mode == APPEND && existingGo:
+		return NONE, fmt.Errorf("can not append to the go file %s, use the mode MERGE or OVERWRITE", target)
+	}
+	return parser.Mode, nil
 }
 
-func (parser *CreateTableSQLParser) getFileHandler(target string) (*os.File, error) {
-	switch parser.Mode {
+func (parser *CreateTableSQLParser) getFileHandler(target string, mode WriteMode) (*os.File, error) {
+	switch mode {
 	case APPEND:
 		return os.OpenFile(target, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
-	case OVERWRITE:
+	case OVERWRITE, MERGE:
 		return os.OpenFile(target, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
 	}
 	return nil, fmt.Errorf("write mode should be one of %v", AllowedMode)
 }
 
-func (parser *CreateTableSQLParser) output(str string) error {
-	targetFile := parser.TargetDir + "/generator.go"
-	file, err := parser.getFileHandler(targetFile)
+// outputFile returns the file the code is written to, StdoutPath for the standard output
+func (parser *CreateTableSQLParser) outputFile() string {
+	if parser.OutputFile == "" {
+		return parser.TargetDir + "/" + parser.FileName
+	}
+	return parser.OutputFile
+}
+
+func (parser *CreateTableSQLParser) output(targetFile string, content []byte) error {
+	existing, err := ioutil.ReadFile(targetFile)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	exist := err == nil
+	mode, err := parser.writeMode(targetFile, existing)
+	if err != nil {
+		return err
+	}
+	if mode == MERGE && exist {
+		if filepath.Ext(targetFile) != ".go" {
+			return fmt.Errorf("only go files can be merged, %s", targetFile)
+		}
+		if content, err = mergeGoFile(existing, content); err != nil {
+			return fmt.Errorf("merge %s failed, err: %v", targetFile, err)
+		}
+	}
+	if parser.DryRun || parser.Diff {
+		if mode == APPEND {
+			content = append(append([]byte(nil), existing...), content...)
+		}
+		return parser.preview(targetFile, existing, exist, content)
+	}
+
+	file, err := parser.getFileHandler(targetFile, mode)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
-	fmt.Printf("Output: %s\n", targetFile)
+	fmt.Fprintf(stderr, "Output: %s\n", targetFile)
 
-	file.WriteString(str)
-	return nil
+	_, err = file.Write(content)
+	return err
+}
+
+// preview prints the status of the target file or its diff, the existing content is replaced with content
+func (parser *CreateTableSQLParser) preview(targetFile string, existing []byte, exist bool, content []byte) error {
+	if parser.Diff {
+		oldFile := targetFile
+		if !exist {
+			oldFile = "/dev/null"
+		}
+		_, err := io.WriteString(stdout, unifiedDiff(oldFile, targetFile, existing, content))
+		return err
+	}
+	status := "unchanged"
+	if !exist {
+		status = "new file"
+	} else if !bytes.Equal(existing, content) {
+		status = "modified"
+	}
+	_, err := fmt.Fprintf(stdout, "%s: %s\n", targetFile, status)
+	return err
+}
+
+// files returns the generated file, or a file for each table named by the FileName pattern when PerTable is set
+func (parser *CreateTableSQLParser) files() ([]File, error) {
+	if !parser.PerTable {
+		code, err := parser.format()
+		if err != nil {
+			return nil, err
+		}
+		return []File{{Name: filepath.Base(parser.outputFile()), Content: code}}, nil
+	}
+	var res []File
+	tables := make(map[string]string)
+	for i, table := range parser.schema.Tables {
+		name := parser.tableFileName(table, parser.structs[i])
+		if name == "" || name != filepath.Base(name) {
+			return nil, fmt.Errorf("invalid file name %q of table %s", name, table.TableName)
+		}
+		if other, exist := tables[name]; exist {
+			return nil, fmt.Errorf("tables %s and %s are generated into the same file %s", other, table.TableName, name)
+		}
+		tables[name] = table.TableName
+
+		one := *parser
+		one.OutputFile = parser.TargetDir + "/" + name
+		one.schema.Tables = parser.schema.Tables[i : i+1]
+		one.structs = parser.structs[i : i+1]
+		code, err := one.format()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, File{Name: name, Content: code})
+	}
+	return res, nil
+}
+
+// tableFileName replaces {table}, {name} and {struct} of the FileName pattern with the table name,
+// the table name without prefix and suffix and the struct name of the table
+func (parser *CreateTableSQLParser) tableFileName(table *TableStruct, ss *SS) string {
+	return strings.NewReplacer(
+		"{table}", table.TableName,
+		"{name}", parser.cleanTableName(table.TableName),
+		"{struct}", ss.StructName,
+	).Replace(parser.FileName)
 }
 
 func (parser *CreateTableSQLParser) getConvertFunc() ConvertFunc {
@@ -287,17 +718,10 @@ func (parser *CreateTableSQLParser) fromTableStruct2SS(s *TableStruct, converter
 		clearnFieldName := parser.cleanFieldName(field.FieldName)
 		sField := &SSField{
 			FieldName: converter(clearnFieldName),
-			FiledType: mapping.getGoStructType(field.FieldType).getString(),
+			FiledType: parser.getGoType(field).getString(),
 		}
 
-		var tmp []string
-		for _, tag := range parser.Tags {
-			tmp = append(tmp, fmt.Sprintf("%s:\"%s\"", tag, clearnFieldName))
-		}
-		if field.FieldComment != "" {
-			tmp = append(tmp, fmt.Sprintf("%s:\"%s\"", parser.CommentTag, field.FieldComment))
-		}
-		sField.Comment = wrapperBackQuote(strings.Join(tmp, " "))
+		sField.Comment = wrapperBackQuote(parser.fieldTag(field))
 		res.Fields = append(res.Fields, sField)
 	}
 
@@ -305,7 +729,38 @@ func (parser *CreateTableSQLParser) fromTableStruct2SS(s *TableStruct, converter
 
 }
 
+// fieldTag returns the struct tag of the field without quotes, e.g. json:"id" db:"id"
+func (parser *CreateTableSQLParser) fieldTag(field *FieldInfo) string {
+	var tmp []string
+	for _, tag := range parser.Tags {
+		tmp = append(tmp, fmt.Sprintf("%s:%s", tag, strconv.Quote(parser.cleanFieldName(field.FieldName))))
+	}
+	if field.FieldComment != "" {
+		tmp = append(tmp, fmt.Sprintf("%s:%s", parser.CommentTag, strconv.Quote(field.FieldComment)))
+	}
+	return strings.Join(tmp, " ")
+}
+
+func (parser *CreateTableSQLParser) getGoType(field *FieldInfo) MappedGoFieldType {
+	goType := parser.Dialect.typeMapper().getGoStructType(field.FieldType)
+	if field.Array {
+		goType = "[]" + goType
+	}
+	if !field.Nullable {
+		return goType
+	}
+	strategy := parser.NullStrategy
+	if s, exist := parser.NullOverrides[goType]; exist {
+		strategy = s
+	}
+	return strategy.wrap(goType)
+}
+
+// wrapperBackQuote returns the tag as a raw string literal, or an interpreted one when it contains a back quote
 func wrapperBackQuote(str string) string {
+	if strings.Contains(str, "`") {
+		return strconv.Quote(str)
+	}
 	return "`" + str + "`"
 }
 
@@ -317,135 +772,140 @@ func (parser *CreateTableSQLParser) cleanFieldName(tableName string) string {
 	return strings.TrimSuffix(strings.TrimPrefix(tableName, parser.FieldNamePrefix), parser.FieldNameSuffix)
 }
 
-func extractTableStruct(sql string) (*TableStruct, error) {
-	rs := []rune(sql)
-
-	var (
-		i            = 0
-		preChar      = ' '
-		currChar     = ' '
-		preGroupFlag = GroupFlagDefault
-	)
-
-	findNextElementFunc := func() (GroupFlag, string) {
-
-		var (
-			cs    []rune
-			start bool
-		)
-
-		for ; i < len(rs); i++ {
-			preChar = currChar
-			currChar = rs[i]
-
-			isPreSep := isSep(preChar)
-			isCurrSep := isSep(currChar)
-			groupFlag := getGroupFlag(currChar)
-
-			if isPreSep && isCurrSep && !start {
-				continue
-			}
-			if (!isPreSep && isCurrSep && groupFlag == preGroupFlag) ||
-				((groupFlag == GroupFlagSingleQuote || groupFlag == GroupFlagBackQuote) && groupFlag == preGroupFlag) {
-				preGroupFlag = GroupFlagUnknown
-				i += 1
-				return groupFlag, string(cs)
-
-			}
-			if isPreSep && !isCurrSep && !start {
-				preGroupFlag = getGroupFlag(preChar)
-				start = true
-			}
+// extractTableStruct parses a CREATE TABLE statement into TableStruct, nil is returned
+// when the statement does not create a table
+func extractTableStruct(sql string, opts Options) (*TableStruct, error) {
+	stmt, err := parseCreateTable(sql, opts)
+	if err != nil {
+		return nil, err
+	}
+	if stmt == nil {
+		return nil, nil
+	}
+	return stmt.toTableStruct(), nil
+}
 
-			// fmt.Println(string(currChar))
-			cs = append(cs, currChar)
+func (stmt *CreateTableStmt) toTableStruct() *TableStruct {
+	table := &TableStruct{
+		TableName: stmt.Table,
+	}
+	for _, option := range stmt.Options {
+		if option.Name == "COMMENT" {
+			table.Comment = option.Value
 		}
-		return GroupFlagUnknown, ""
 	}
-
-	var (
-		elementTypes    []Element
-		elementValues   []string
-		preElementValue string
-		preFlag         GroupFlag
-	)
-	for {
-		flag, elementStr := findNextElementFunc()
-		if elementStr == "" {
-			break
+	for _, column := range stmt.Columns {
+		if field := column.toFieldInfo(); field != nil {
+			table.Fields = append(table.Fields, field)
 		}
-		if flag == GroupFlagBackQuote {
-			if (preFlag == GroupFlagBackQuote && len(elementTypes) > 1) || strings.ToLower(preElementValue) == "key" {
-				break
-			}
-			elementValues = append(elementValues, elementStr)
-			if len(elementTypes) == 0 {
-				elementTypes = append(elementTypes, ElementTable)
-			} else {
-				elementTypes = append(elementTypes, ElementField)
-			}
-		} else {
-			if preFlag == GroupFlagBackQuote {
-				elementTypes = append(elementTypes, ElementFieldType)
-				elementValues = append(elementValues, elementStr)
-			}
-			if strings.ToLower(preElementValue) == "comment" {
-				elementTypes = append(elementTypes, ElementComment)
-				elementValues = append(elementValues, elementStr)
-			}
+	}
+	for _, column := range stmt.Columns {
+		for _, index := range column.indexes() {
+			table.addIndex(index)
 		}
-
-		preElementValue = elementStr
-		preFlag = flag
 	}
-
-	var field *FieldInfo
-	table := &TableStruct{}
-	for k := 0; k < len(elementTypes); k++ {
-		value := elementValues[k]
-		switch elementTypes[k] {
-		case ElementTable:
-			table.TableName = value
-		case ElementField:
-			if field != nil && field.FieldType != "" && field.FieldName != "" {
-				table.Fields = append(table.Fields, field)
-			}
-			field = &FieldInfo{
-				FieldName: value,
-			}
-		case ElementFieldType:
-			field.FieldType = value
-		case ElementComment:
-			field.FieldComment = value
+	for _, constraint := range stmt.Constraints {
+		table.addIndex(constraint.toIndexInfo())
+	}
+	for _, column := range stmt.Columns {
+		if foreignKey := column.foreignKey(); foreignKey != nil {
+			table.ForeignKeys = append(table.ForeignKeys, foreignKey)
 		}
 	}
-	if field != nil && field.FieldType != "" && field.FieldName != "" {
-		table.Fields = append(table.Fields, field)
+	for _, foreignKey := range stmt.ForeignKeys {
+		table.ForeignKeys = append(table.ForeignKeys, foreignKey.toForeignKeyInfo())
 	}
-	return table, nil
+	return table
 }
 
-func IsFieldOrTableName(str string) bool {
-	return strings.HasPrefix(str, "`") || strings.HasSuffix(str, "`")
+// toFieldInfo converts the column definition into a field, nil is returned when the column has no type
+func (column *ColumnDef) toFieldInfo() *FieldInfo {
+	if column.Type == nil {
+		return nil
+	}
+	field := &FieldInfo{
+		FieldName:     column.Name,
+		FieldType:     column.Type.Name,
+		FieldComment:  column.Comment,
+		Array:         column.Type.Array,
+		Nullable:      !column.NotNull && !column.PrimaryKey,
+		Default:       column.Default,
+		AutoIncrement: column.AutoIncrement,
+		Unsigned:      column.Unsigned,
+		Charset:       column.Charset,
+		Collation:     column.Collation,
+		OnUpdate:      column.OnUpdate,
+	}
+	field.setTypeArgs(column.Type.Args)
+	return field
 }
 
-func getGroupFlag(s rune) GroupFlag {
-	if unicode.IsSpace(s) || s == '(' || s == ')' {
-		return GroupFlagDefault
+// indexes returns the keys declared inline by PRIMARY KEY and UNIQUE of the column
+func (column *ColumnDef) indexes() []*IndexInfo {
+	var res []*IndexInfo
+	if column.PrimaryKey {
+		res = append(res, &IndexInfo{Kind: IndexPrimary, Columns: []string{column.Name}})
 	}
-	if s == '\'' {
-		return GroupFlagSingleQuote
+	if column.Unique {
+		res = append(res, &IndexInfo{Kind: IndexUnique, Columns: []string{column.Name}})
 	}
-	if s == '`' {
-		return GroupFlagBackQuote
+	return res
+}
+
+// foreignKey returns the foreign key declared inline by REFERENCES of the column, nil if there is none
+func (column *ColumnDef) foreignKey() *ForeignKeyInfo {
+	if column.References == nil {
+		return nil
+	}
+	foreignKey := *column.References
+	foreignKey.Columns = []string{column.Name}
+	return foreignKey.toForeignKeyInfo()
+}
+
+func (def *ConstraintDef) toIndexInfo() *IndexInfo {
+	return &IndexInfo{
+		Name:    def.Name,
+		Kind:    def.Kind,
+		Columns: def.Columns,
 	}
-	return GroupFlagUnknown
 }
 
-func is_group_flag(s rune) bool {
-	return s == '`' || s == '\''
+func (def *ForeignKeyDef) toForeignKeyInfo() *ForeignKeyInfo {
+	return &ForeignKeyInfo{
+		Name:       def.Name,
+		Columns:    def.Columns,
+		RefTable:   def.RefTable,
+		RefColumns: def.RefColumns,
+		OnDelete:   def.OnDelete,
+		OnUpdate:   def.OnUpdate,
+	}
 }
 
-func isSep(s rune) bool {
-	return unicode.IsSpace(s) || s == '(' || s == ')' || s == ',' || is_group_flag(s)
+// precisionTypes take (precision, scale) as arguments instead of a length
+var precisionTypes = map[string]struct{}{
+	"DECIMAL": {}, "DEC": {}, "NUMERIC": {}, "FLOAT": {}, "DOUBLE": {}, "REAL": {},
+	"DATETIME": {}, "TIMESTAMP": {}, "TIME": {}, "TIMESTAMPTZ": {}, "TIMETZ": {}, "DATETIME2": {}, "DATETIMEOFFSET": {},
+}
+
+func (field *FieldInfo) setTypeArgs(args []string) {
+	var nums []int
+	for _, arg := range args {
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return
+		}
+		nums = append(nums, n)
+	}
+	if len(nums) == 0 {
+		return
+	}
+	// multi-word types are looked up by the first word, e.g. timestamp with time zone
+	if _, exist := precisionTypes[strings.ToUpper(strings.Fields(field.FieldType)[0])]; !exist {
+		field.Length = nums[0]
+		return
+	}
+	field.Precision = nums[0]
+	if len(nums) > 1 {
+		field.Scale = nums[1]
+	}
 }
@@ -1,7 +1,14 @@
-package main
+package sqlconverter
 
 import (
+	"bytes"
 	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -9,50 +16,88 @@ import (
 
 func TestExtractTableStruct(t *testing.T) {
 	inputSQLs := []string{
-		"CREATE TABLE IF NOT EXISTS `v_test_table` (`id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键, 无实际意义',`student_name` VARCHAR(128) NOT NULL COMMENT '学生姓名', `created_at` TIMESTAMP NOT NULL CURRENT_STAMP ON UPDATE CURRENT_STAMP) ENGINE=InnoDB COMMENT='测试表'",
+		"CREATE TABLE IF NOT EXISTS `v_test_table` (`id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键, 无实际意义',`student_name` VARCHAR(128) NOT NULL COMMENT '学生姓名', `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_STAMP ON UPDATE CURRENT_STAMP) ENGINE=InnoDB COMMENT='测试表'",
 		"CREATE TABLE IF NOT EXISTS `v_test_table` (`id` BIGINT(20) UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '主键, 无实际意义',`student_name` VARCHAR(128) NOT NULL COMMENT '学生姓名', PRIMARY KEY `id`, KEY `idx_name` (`student_name`)) ENGINE=InnoDB COMMENT='测试表'",
+		"CREATE TABLE v_test_table (id BIGINT DEFAULT (1 + (2)), KEY idx_id (id), score DECIMAL(10,2) CHECK (score > 0))",
 	}
 	expecteds := []*TableStruct{
 		{
 			TableName: "v_test_table",
+			Comment:   "测试表",
 			Fields: []*FieldInfo{
 				{
-					FieldName:    "id",
-					FieldType:    "BIGINT",
-					FieldComment: "主键, 无实际意义",
+					FieldName:     "id",
+					FieldType:     "BIGINT",
+					FieldComment:  "主键, 无实际意义",
+					AutoIncrement: true,
+					Unsigned:      true,
+					Length:        20,
 				},
 				{
 					FieldName:    "student_name",
 					FieldType:    "VARCHAR",
 					FieldComment: "学生姓名",
+					Length:       128,
 				},
 				{
 					FieldName:    "created_at",
 					FieldType:    "TIMESTAMP",
 					FieldComment: "",
+					Default:      stringPtr("CURRENT_STAMP"),
+					OnUpdate:     "CURRENT_STAMP",
 				},
 			},
 		},
 		{
 			TableName: "v_test_table",
+			Comment:   "测试表",
 			Fields: []*FieldInfo{
 				{
-					FieldName:    "id",
-					FieldType:    "BIGINT",
-					FieldComment: "主键, 无实际意义",
+					FieldName:     "id",
+					FieldType:     "BIGINT",
+					FieldComment:  "主键, 无实际意义",
+					AutoIncrement: true,
+					Unsigned:      true,
+					Length:        20,
 				},
 				{
 					FieldName:    "student_name",
 					FieldType:    "VARCHAR",
 					FieldComment: "学生姓名",
+					Length:       128,
 				},
 			},
+			PrimaryKey: &IndexInfo{Kind: IndexPrimary, Columns: []string{"id"}},
+			Indexes: []*IndexInfo{
+				{Name: "idx_name", Kind: IndexNormal, Columns: []string{"student_name"}},
+			},
+		},
+		{
+			TableName: "v_test_table",
+			Fields: []*FieldInfo{
+				{
+					FieldName: "id",
+					FieldType: "BIGINT",
+					Nullable:  true,
+					Default:   stringPtr("(1 + (2))"),
+				},
+				{
+					FieldName: "score",
+					FieldType: "DECIMAL",
+					Nullable:  true,
+					Precision: 10,
+					Scale:     2,
+				},
+			},
+			Indexes: []*IndexInfo{
+				{Name: "idx_id", Kind: IndexNormal, Columns: []string{"id"}},
+			},
 		},
 	}
 
 	for idx, sql := range inputSQLs {
 		t.Run(fmt.Sprintf("Case %d", idx), func(t *testing.T) {
-			actual, err := extractTableStruct(sql)
+			actual, err := extractTableStruct(sql, Options{})
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -61,6 +106,26 @@ func TestExtractTableStruct(t *testing.T) {
 	}
 }
 
+func TestExtractTableStructKeys(t *testing.T) {
+	sql := "CREATE TABLE t (`id` INT PRIMARY KEY, `email` VARCHAR(64) UNIQUE, `a` INT, `b` INT, " +
+		"UNIQUE KEY `uk_ab` (`a`, `b`), KEY `idx_b` (`b`))"
+	actual, err := extractTableStruct(sql, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, &IndexInfo{Kind: IndexPrimary, Columns: []string{"id"}}, actual.PrimaryKey)
+	assert.Equal(t, []*IndexInfo{
+		{Kind: IndexUnique, Columns: []string{"email"}},
+		{Name: "uk_ab", Kind: IndexUnique, Columns: []string{"a", "b"}},
+	}, actual.UniqueIndexes())
+	assert.Len(t, actual.Indexes, 3)
+	assert.True(t, actual.IsPrimaryKey("id"))
+	assert.False(t, actual.IsPrimaryKey("email"))
+	assert.False(t, actual.GetField("id").Nullable)
+	assert.True(t, actual.GetField("email").Nullable)
+}
+
 func TestDefaultConvertFunc(t *testing.T) {
 	input := "v_test_table"
 	expected := "VTestTable"
@@ -125,31 +190,60 @@ func TestFromTableStruct2SS(t *testing.T) {
 
 }
 
-func TestFormatOne(t *testing.T) {
-	input := &SS{
-		StructName: "TestTable",
-		Fields: []*SSField{
-			{
-				FieldName: "Field1",
-				FiledType: "string",
-				Comment:   "测试",
+func TestStructDecl(t *testing.T) {
+	inputs := []*SS{
+		{
+			StructName: "TestTable",
+			Fields: []*SSField{
+				{FieldName: "Field1", FiledType: "string", Comment: "`comment:\"测试\"`"},
+				{FieldName: "Field2", FiledType: "int64", Comment: "`comment:\"测试int64\"`"},
 			},
-			{
-				FieldName: "Field2",
-				FiledType: "int64",
-				Comment:   "测试int64",
+		},
+		{
+			StructName: "Users",
+			Fields: []*SSField{
+				{FieldName: "名字", FiledType: "*string", Comment: "`json:\"名字\"`"},
+				{FieldName: "CreatedAt", FiledType: "[]time.Time", Comment: wrapperBackQuote("alias:\"`a`\"")},
 			},
 		},
 	}
-	expected :=
-		`type TestTable struct {
-	Field1 string 测试
-	Field2 int64  测试int64
-}`
-	parser := &CreateTableSQLParser{}
+	expecteds := []string{
+		"type TestTable struct {\n" +
+			"\tField1 string `comment:\"测试\"`\n" +
+			"\tField2 int64  `comment:\"测试int64\"`\n" +
+			"}",
+		"type Users struct {\n" +
+			"\t名字        *string     `json:\"名字\"`\n" +
+			"\tCreatedAt []time.Time \"alias:\\\"`a`\\\"\"\n" +
+			"}",
+	}
+	for i, input := range inputs {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			decl, err := input.decl()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var b bytes.Buffer
+			if err := format.Node(&b, token.NewFileSet(), decl); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, expecteds[i], b.String())
+		})
+	}
 
-	actual := parser.formatOne(input)
-	assert.Equal(t, expected, actual)
+	invalids := []*SS{
+		{StructName: "2fa"},
+		{StructName: "Users", Fields: []*SSField{{FieldName: "user-id", FiledType: "int64"}}},
+		{StructName: "Users", Fields: []*SSField{{FieldName: "ID", FiledType: "int64)"}}},
+		{StructName: "Users", Fields: []*SSField{{FieldName: "ID", FiledType: "uuid.UUID"}}},
+	}
+	for i, input := range invalids {
+		t.Run(fmt.Sprintf("Invalid %d", i), func(t *testing.T) {
+			parser := &CreateTableSQLParser{PackageName: "models", structs: []*SS{input}}
+			_, err := parser.format()
+			assert.Error(t, err)
+		})
+	}
 }
 
 func TestReadCreateSQL(t *testing.T) {
@@ -157,58 +251,216 @@ func TestReadCreateSQL(t *testing.T) {
 	expected := []string{
 		"CREATE TABLE `v_test_1`()",
 		"CREATE TABLE `v_test_2`",
+		"ALTER TABLE `v_test_1` ADD INDEX `idx_q`(`q`)",
 	}
-	actual, err := readCreateSQL([]byte(sql))
+	actual, err := readCreateSQL([]byte(sql), MySQL)
 	if err != nil {
 		t.Fatal(err)
 	}
 	assert.Equal(t, expected, actual)
 }
 
-func TestParseArg(t *testing.T) {
-	cases := map[string][]string{
-		"Case1": {
-			"-aa", "-file",
-		},
-		"Case2": {
-			"./test.sql", "-aa", "-file",
-		},
-		"Case3": {
-			"./test.sql",
-			"-tags=json,db",
-			"-comment_tag",
-		},
-		"Case4": {
-			"./test.sql",
-			"-tags=json,db",
-			"-comment_tag=comment",
-			"-field_prefix=v_",
-			"-table_prefix=v_",
-			"-table_suffix=v_=v_",
-		},
+func TestGetGoType(t *testing.T) {
+	inputs := []*FieldInfo{
+		{FieldName: "a", FieldType: "varchar"},
+		{FieldName: "b", FieldType: "varchar", Nullable: true},
+		{FieldName: "c", FieldType: "datetime", Nullable: true},
+		{FieldName: "d", FieldType: "bigint", Nullable: true},
+	}
+	expecteds := []MappedGoFieldType{
+		"string",
+		"sql.NullString",
+		"*time.Time",
+		"int64",
 	}
-	expected := map[string]map[string]string{
-		"Case1": nil,
-		"Case2": nil,
-		"Case3": {
-			"-file":        "./test.sql",
-			"-tags":        "json,db",
-			"-comment_tag": "",
+
+	parser := &CreateTableSQLParser{
+		NullStrategy: NullSQL,
+		NullOverrides: map[MappedGoFieldType]NullStrategy{
+			FieldTypeTime:  NullPointer,
+			FeildTypeInt64: NullNone,
 		},
-		"Case4": {
-			"-file":         "./test.sql",
-			"-tags":         "json,db",
-			"-comment_tag":  "comment",
-			"-field_prefix": "v_",
-			"-table_prefix": "v_",
-			"-table_suffix": "v_",
+	}
+	for i, input := range inputs {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			assert.Equal(t, expecteds[i], parser.getGoType(input))
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	parser := &CreateTableSQLParser{
+		PackageName: "models",
+		schema:      Schema{Tables: []*TableStruct{{TableName: "test_table"}}},
+		structs: []*SS{
+			{
+				StructName: "TestTable",
+				Fields: []*SSField{
+					{FieldName: "Name", FiledType: "sql.NullString", Comment: "`db:\"name\"`"},
+					{FieldName: "CreatedAt", FiledType: "time.Time", Comment: "`db:\"created_at\"`"},
+				},
+			},
 		},
 	}
+	expected := `package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// table: test_table
+type TestTable struct {
+	Name      sql.NullString ` + "`db:\"name\"`" + `
+	CreatedAt time.Time      ` + "`db:\"created_at\"`" + `
+}
+`
+
+	code, err := parser.format()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, expected, string(code))
+}
+
+func TestLoadMysqldump(t *testing.T) {
+	parser := &CreateTableSQLParser{
+		SqlFile: "./testdata/mysqldump.sql",
+	}
+	parser.SetDefault()
+	if err := parser.load(); err != nil {
+		t.Fatal(err)
+	}
+	if err := parser.parseSQL(); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, table := range parser.schema.Tables {
+		names = append(names, table.TableName)
+	}
+	assert.Equal(t, []string{"users", "orders"}, names)
+
+	users, orders := parser.schema.Tables[0], parser.schema.Tables[1]
+	assert.Equal(t, "Users", users.Comment)
+	assert.Equal(t, "Display; Name", users.GetField("name").FieldComment)
+	assert.Len(t, users.Fields, 4)
+	assert.Equal(t, []string{"id", "year"}, orders.PrimaryKey.Columns)
+	assert.Equal(t, "CASCADE", orders.ForeignKeys[0].OnDelete)
+	assert.Len(t, parser.relations, 2)
+}
+
+func TestParseStdout(t *testing.T) {
+	var out, info bytes.Buffer
+	stdout, stderr = &out, &info
+	defer func() {
+		stdout, stderr = os.Stdout, os.Stderr
+	}()
+
+	parser := &CreateTableSQLParser{
+		Sqls:       []string{"CREATE TABLE users (id int NOT NULL)"},
+		OutputFile: StdoutPath,
+	}
+	if err := parser.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "package sqlconverter\n\n// table: users\ntype Users struct {\n\tID int32 `json:\"id\" db:\"id\"`\n}\n", out.String())
+	assert.Empty(t, info.String())
+
+	parser = &CreateTableSQLParser{
+		Sqls:        []string{"CREATE TABLE users (id int NOT NULL)"},
+		OutputFile:  StdoutPath,
+		PackageName: "1models",
+	}
+	assert.Error(t, parser.Parse())
+}
+
+func TestParsePerTable(t *testing.T) {
+	var info bytes.Buffer
+	stderr = &info
+	defer func() {
+		stderr = os.Stderr
+	}()
+
+	dir := t.TempDir()
+	parser := &CreateTableSQLParser{
+		Sqls:        []string{"CREATE TABLE users (id int NOT NULL)", "CREATE TABLE orders (id int NOT NULL)"},
+		TargetDir:   dir,
+		PackageName: "models",
+		PerTable:    true,
+		FileName:    "{table}_gen.go",
+	}
+	if err := parser.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "orders_gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "package models\n\n// table: orders\ntype Orders struct {\n\tID int32 `json:\"id\" db:\"id\"`\n}\n", string(b))
+	assert.Equal(t, fmt.Sprintf("Output: %s/users_gen.go\nOutput: %s/orders_gen.go\n", dir, dir), info.String())
 
-	for name, args := range cases {
-		t.Run(name, func(t *testing.T) {
-			parser, _ := parseArg(args)
-			assert.Equal(t, expected[name], parser)
+	// the tables can not be written to a single output, which is checked before the sources are read
+	for _, output := range []string{StdoutPath, filepath.Join(dir, "models.go")} {
+		parser = &CreateTableSQLParser{
+			SqlFile:    filepath.Join(dir, "unknown.sql"),
+			TargetDir:  dir,
+			PerTable:   true,
+			OutputFile: output,
+		}
+		assert.EqualError(t, parser.Parse(), "the output file can not be set when a file is generated for each table")
+	}
+}
+
+func TestParseRegenerate(t *testing.T) {
+	var info bytes.Buffer
+	stderr = &info
+	defer func() {
+		stderr = os.Stderr
+	}()
+
+	dir := t.TempDir()
+	parse := func(perTable bool, sqls ...string) error {
+		parser := &CreateTableSQLParser{
+			Sqls:         sqls,
+			TargetDir:    dir,
+			PackageName:  "models",
+			PerTable:     perTable,
+			NullStrategy: NullSQL,
+		}
+		return parser.Parse()
+	}
+	// the existing go files are merged by default, so they still compile after they are generated again
+	for _, perTable := range []bool{false, true} {
+		t.Run(fmt.Sprintf("PerTable %v", perTable), func(t *testing.T) {
+			for _, sql := range []string{
+				"CREATE TABLE users (id int NOT NULL)",
+				"CREATE TABLE users (id int NOT NULL, name varchar(32), created_at datetime NOT NULL)",
+				"CREATE TABLE users (id int NOT NULL, name varchar(32))",
+			} {
+				if err := parse(perTable, sql); err != nil {
+					t.Fatal(err)
+				}
+			}
+			paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, path := range paths {
+				f, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				assert.Len(t, f.Decls, 2) // the import and the struct
+			}
 		})
 	}
+
+	// the generated code can not be appended to the existing go file
+	parser := &CreateTableSQLParser{
+		Sqls:      []string{"CREATE TABLE users (id int NOT NULL)"},
+		TargetDir: dir,
+		Mode:      APPEND,
+	}
+	assert.Error(t, parser.Parse())
 }
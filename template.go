@@ -0,0 +1,200 @@
+package sqlconverter
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// TemplateData is the data a template is executed on
+type TemplateData struct {
+	Package   string
+	Dialect   Dialect
+	Imports   []string // the sorted import paths of the go types of the columns
+	Tables    []*TemplateTable
+	Relations []*Relation
+}
+
+// TemplateTable is a table with the names of its struct, its TableName, Comment, PrimaryKey, Indexes
+// and ForeignKeys are the ones of TableStruct
+type TemplateTable struct {
+	*TableStruct
+	StructName string
+	Columns    []*TemplateColumn
+	Relations  []*Relation // the relations owned by the table
+}
+
+// TemplateColumn is a column with its go field, its FieldName, FieldType, Nullable, Default... are the ones of FieldInfo
+type TemplateColumn struct {
+	*FieldInfo
+	GoName string
+	GoType string // the go type after the null strategy is applied
+	Tag    string // the struct tag without quotes, e.g. json:"id" db:"id"
+}
+
+// render executes the template on the schema instead of generating the structs, the result is formatted
+// by go/format when it is written to a go file
+func (parser *CreateTableSQLParser) render() ([]byte, error) {
+	name, text := "template", parser.templateText
+	if text == "" {
+		b, err := ioutil.ReadFile(parser.TemplateFile)
+		if err != nil {
+			return nil, fmt.Errorf("read template failed, err: %v", err)
+		}
+		name, text = filepath.Base(parser.TemplateFile), string(b)
+	}
+	tmpl, err := template.New(name).Funcs(parser.templateFuncs()).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse template failed, err: %v", err)
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, parser.templateData()); err != nil {
+		return nil, fmt.Errorf("execute template failed, err: %v", err)
+	}
+	// go code is formatted wherever it is written, the other artifacts are written as they are
+	src, err := format.Source(b.Bytes())
+	switch {
+	case err == nil:
+		return src, nil
+	case filepath.Ext(parser.outputFile()) == ".go":
+		return nil, fmt.Errorf("rendered code is invalid, err: %v", err)
+	}
+	return b.Bytes(), nil
+}
+
+// templateData pairs the tables of the schema with their generated structs
+func (parser *CreateTableSQLParser) templateData() *TemplateData {
+	data := &TemplateData{
+		Package:   parser.PackageName,
+		Dialect:   parser.Dialect,
+		Relations: parser.relations,
+	}
+	set := make(map[string]struct{})
+	for i, table := range parser.schema.Tables {
+		ss := parser.structs[i]
+		t := &TemplateTable{
+			TableStruct: table,
+			StructName:  ss.StructName,
+			Relations:   relationsOf(parser.relations, table.TableName),
+		}
+		for j, field := range table.Fields {
+			column := &TemplateColumn{
+				FieldInfo: field,
+				GoName:    ss.Fields[j].FieldName,
+				GoType:    ss.Fields[j].FiledType,
+				Tag:       parser.fieldTag(field),
+			}
+			if path := MappedGoFieldType(column.GoType).importPath(); path != "" {
+				set[path] = struct{}{}
+			}
+			t.Columns = append(t.Columns, column)
+		}
+		data.Tables = append(data.Tables, t)
+	}
+	for path := range set {
+		data.Imports = append(data.Imports, path)
+	}
+	sort.Strings(data.Imports)
+	return data
+}
+
+// templateFuncs returns the helpers of the templates
+func (parser *CreateTableSQLParser) templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"camel":      defaultConvertFunc,
+		"lowerCamel": lowerCamel,
+		"snake":      snake,
+		"plural":     plural,
+		"singular":   singular,
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"quote":      strconv.Quote,
+		"join": func(sep string, elems []string) string {
+			return strings.Join(elems, sep)
+		},
+		// tag builds a key:"value" pair and tags joins the non-empty pairs into a struct tag literal
+		"tag": func(key, value string) string {
+			return key + ":" + strconv.Quote(value)
+		},
+		"tags": func(pairs ...string) string {
+			var res []string
+			for _, pair := range pairs {
+				if pair != "" {
+					res = append(res, pair)
+				}
+			}
+			return wrapperBackQuote(strings.Join(res, " "))
+		},
+		// goType and nullType look up the go type of a sql type of the dialect, nullType applies the null strategy
+		"goType": func(sqlType string) string {
+			return parser.getGoType(&FieldInfo{FieldType: sqlType}).getString()
+		},
+		"nullType": func(sqlType string) string {
+			return parser.getGoType(&FieldInfo{FieldType: sqlType, Nullable: true}).getString()
+		},
+	}
+}
+
+// lowerCamel returns the lower camel case of the name, e.g. user_id becomes userID and id_card becomes idCard
+func lowerCamel(name string) string {
+	runes := []rune(defaultConvertFunc(name))
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		// the last upper letter of an abbreviation starts the next word, e.g. IDCard
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// snake returns the snake case of the name, e.g. UserID becomes user_id
+func snake(name string) string {
+	runes := []rune(name)
+	var res []rune
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 && runes[i-1] != '_' &&
+			(!unicode.IsUpper(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			res = append(res, '_')
+		}
+		res = append(res, unicode.ToLower(r))
+	}
+	return string(res)
+}
+
+// plural returns the plural of an english noun by the regular rules, e.g. category becomes categories
+func plural(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case lower == "":
+		return name
+	case strings.HasSuffix(lower, "s") || strings.HasSuffix(lower, "x") || strings.HasSuffix(lower, "z") ||
+		strings.HasSuffix(lower, "ch") || strings.HasSuffix(lower, "sh"):
+		return name + "es"
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return name[:len(name)-1] + "ies"
+	}
+	return name + "s"
+}
+
+// singular returns the singular of an english noun by the regular rules, e.g. categories becomes category
+func singular(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, "ies") && len(lower) > 3:
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(lower, "ses") || strings.HasSuffix(lower, "xes") || strings.HasSuffix(lower, "zes") ||
+		strings.HasSuffix(lower, "ches") || strings.HasSuffix(lower, "shes"):
+		return name[:len(name)-2]
+	case strings.HasSuffix(lower, "s") && !strings.HasSuffix(lower, "ss"):
+		return name[:len(name)-1]
+	}
+	return name
+}
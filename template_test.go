@@ -0,0 +1,159 @@
+package sqlconverter
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNameFuncs(t *testing.T) {
+	inputs := []string{"user_id", "id_card", "id", "order_items", "UserID", "HTTPServer", "category", "box", "day"}
+	lowerCamels := []string{"userID", "idCard", "id", "orderItems", "userID", "httpServer", "category", "box", "day"}
+	snakes := []string{"user_id", "id_card", "id", "order_items", "user_id", "http_server", "category", "box", "day"}
+	plurals := []string{"user_ids", "id_cards", "ids", "order_itemses", "UserIDs", "HTTPServers", "categories", "boxes", "days"}
+	for i, input := range inputs {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			assert.Equal(t, lowerCamels[i], lowerCamel(input))
+			assert.Equal(t, snakes[i], snake(input))
+			assert.Equal(t, plurals[i], plural(input))
+		})
+	}
+
+	singulars := map[string]string{
+		"categories": "category", "boxes": "box", "matches": "match", "users": "user", "address": "address", "user": "user",
+	}
+	for input, expected := range singulars {
+		assert.Equal(t, expected, singular(input))
+	}
+}
+
+func TestGenerateTemplate(t *testing.T) {
+	sql := "CREATE TABLE order_items (id bigint NOT NULL, order_id bigint NOT NULL, price decimal(10, 2), " +
+		"created_at datetime COMMENT 'create time', PRIMARY KEY (id), " +
+		"FOREIGN KEY (order_id) REFERENCES orders (id));\n" +
+		"CREATE TABLE orders (id bigint NOT NULL PRIMARY KEY)"
+	schema, err := Parse(strings.NewReader(sql), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := `package {{ .Package }}
+import ({{ range .Imports }}{{ quote . }}
+{{ end }})
+{{ range .Tables }}
+// {{ .StructName }} is a row of {{ .TableName }}, the primary key is {{ join ", " .PrimaryKey.Columns }}
+type {{ .StructName }} struct {
+{{- range .Columns }}
+	{{ .GoName }} {{ .GoType }} {{ tags (tag "gorm" (printf "column:%s" .FieldName)) (tag "json" (lowerCamel .FieldName)) }}
+{{- end }}
+}
+{{ range .Relations }}// {{ .Kind }} {{ .RefTable }}
+{{ end }}
+type {{ plural .StructName }} []*{{ .StructName }}
+{{ end }}
+var _ {{ goType "decimal" }} = 0
+var _ {{ nullType "datetime" }}
+`
+	files, err := Generate(schema, GeneratorOptions{Package: "models", Template: tmpl, NullStrategy: NullSQL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `package models
+
+import (
+	"database/sql"
+)
+
+// OrderItems is a row of order_items, the primary key is id
+type OrderItems struct {
+	ID        int64           ` + "`gorm:\"column:id\" json:\"id\"`" + `
+	OrderID   int64           ` + "`gorm:\"column:order_id\" json:\"orderID\"`" + `
+	Price     sql.NullFloat64 ` + "`gorm:\"column:price\" json:\"price\"`" + `
+	CreatedAt sql.NullTime    ` + "`gorm:\"column:created_at\" json:\"createdAt\"`" + `
+}
+
+// BELONGS_TO orders
+
+type OrderItemses []*OrderItems
+
+// Orders is a row of orders, the primary key is id
+type Orders struct {
+	ID int64 ` + "`gorm:\"column:id\" json:\"id\"`" + `
+}
+
+// HAS_MANY order_items
+
+type Orderses []*Orders
+
+var _ float64 = 0
+var _ sql.NullTime
+`
+	assert.Len(t, files, 1)
+	assert.Equal(t, DefaultFileName, files[0].Name)
+	assert.Equal(t, expected, string(files[0].Content))
+
+	_, err = Generate(schema, GeneratorOptions{
+		Template: `{{ range .Tables }}{{ range .Columns }}{{ .StructName }}{{ end }}{{ end }}`,
+	})
+	assert.Error(t, err)
+	_, err = Generate(schema, GeneratorOptions{
+		Template: `{{ range .Tables }}{{ .TableName }}:{{ range .Columns }} {{ .FieldName }} {{ .FieldType }} {{ .Tag }}{{ end }}{{ end }}`,
+	})
+	assert.Error(t, err) // not a go file
+	_, err = Generate(schema, GeneratorOptions{Template: `{{ .Unknown }`})
+	assert.Error(t, err)
+}
+
+func TestParseTemplate(t *testing.T) {
+	var out, info bytes.Buffer
+	stdout, stderr = &out, &info
+	defer func() {
+		stdout, stderr = os.Stdout, os.Stderr
+	}()
+
+	// the template renders other artifacts than go files to the standard output as it is
+	path := filepath.Join(t.TempDir(), "tables.md.tmpl")
+	tmpl := "{{ range .Tables }}| {{ .TableName }} |{{ range .Columns }} {{ .FieldName }} {{ .GoType }}{{ if .Nullable }}?{{ end }} |{{ end }}\n{{ end }}"
+	if err := ioutil.WriteFile(path, []byte(tmpl), 0644); err != nil {
+		t.Fatal(err)
+	}
+	parser := &CreateTableSQLParser{
+		Sqls:         []string{"CREATE TABLE users (id int NOT NULL, name varchar(64))"},
+		TemplateFile: path,
+		OutputFile:   StdoutPath,
+		NullStrategy: NullSQL,
+	}
+	if err := parser.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "| users | id int32 | name sql.NullString? |\n", out.String())
+
+	// and formats go code on the standard output as well
+	out.Reset()
+	if err := ioutil.WriteFile(path, []byte("package {{ .Package }}\n{{ range .Tables }}type {{ .StructName }} struct{ {{ range .Columns }}{{ .GoName }}  {{ .GoType }};{{ end }} }{{ end }}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	parser = &CreateTableSQLParser{
+		Sqls:         []string{"CREATE TABLE users (id int NOT NULL, name varchar(64) NOT NULL)"},
+		TemplateFile: path,
+		OutputFile:   StdoutPath,
+		PackageName:  "models",
+	}
+	if err := parser.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "package models\n\ntype Users struct {\n\tID   int32\n\tName string\n}\n", out.String())
+
+	parser = &CreateTableSQLParser{
+		Sqls:         []string{"CREATE TABLE users (id int NOT NULL)"},
+		TemplateFile: filepath.Join(t.TempDir(), "unknown.tmpl"),
+		OutputFile:   StdoutPath,
+	}
+	assert.Error(t, parser.Parse())
+}
@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN updated_at timestamptz;
+
+-- +goose StatementBegin
+CREATE FUNCTION touch() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at = now();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN updated_at;
+DROP TABLE users;
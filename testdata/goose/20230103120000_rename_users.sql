@@ -0,0 +1,4 @@
+-- +goose Up
+ALTER TABLE users RENAME TO members;
+-- +goose Down
+ALTER TABLE members RENAME TO users;
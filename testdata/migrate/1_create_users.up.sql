@@ -0,0 +1,5 @@
+CREATE TABLE `users` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `mail` varchar(32) NOT NULL,
+  PRIMARY KEY (`id`)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE `users` DROP COLUMN `name`;
+ALTER TABLE `users` CHANGE `email` `mail` varchar(32) NOT NULL;
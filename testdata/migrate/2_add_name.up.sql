@@ -0,0 +1,2 @@
+ALTER TABLE `users` ADD COLUMN `name` varchar(64) NOT NULL AFTER `id`;
+ALTER TABLE `users` CHANGE `mail` `email` varchar(128) NOT NULL;
@@ -0,0 +1,5 @@
+CREATE TABLE `items` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `order_id` bigint unsigned NOT NULL,
+  PRIMARY KEY (`id`)
+);
@@ -1,4 +1,4 @@
-package main
+package sqlconverter
 
 import (
 	"encoding/json"